@@ -5,26 +5,40 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/bluefin"
+	"github.com/castrojo/bluefin-releases/internal/changelog"
+	"github.com/castrojo/bluefin-releases/internal/classify"
 	"github.com/castrojo/bluefin-releases/internal/flathub"
+	"github.com/castrojo/bluefin-releases/internal/gitea"
 	"github.com/castrojo/bluefin-releases/internal/github"
 	"github.com/castrojo/bluefin-releases/internal/gitlab"
+	"github.com/castrojo/bluefin-releases/internal/gittags"
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/merge"
 	"github.com/castrojo/bluefin-releases/internal/models"
 	"github.com/castrojo/bluefin-releases/internal/mozilla"
+	"github.com/castrojo/bluefin-releases/internal/notespage"
+	"github.com/castrojo/bluefin-releases/internal/osv"
+	"github.com/castrojo/bluefin-releases/internal/rss"
+	versions "github.com/castrojo/bluefin-releases/internal/version"
 )
 
 const version = "1.0.0"
 
-// normalizeReleaseDates propagates the latest release date to top-level app fields
-// Ensures app.ReleaseDate and app.UpdatedAt are populated from the actual latest release
+// normalizeReleaseDates sorts each app's releases by version and propagates the latest
+// stable release to top-level app fields (app.Version, app.ReleaseDate and app.UpdatedAt)
 func normalizeReleaseDates(apps []models.App) []models.App {
 	for i := range apps {
 		app := &apps[i]
 
 		if len(app.Releases) > 0 {
-			latest := app.Releases[0]
+			scheme := releaseScheme(*app)
+			sortReleases(app.Releases, scheme)
+			latest := latestStableRelease(app.Releases, scheme)
 
 			// Always update ReleaseDate from latest release
 			app.ReleaseDate = latest.Date.Format(time.RFC3339)
@@ -43,51 +57,172 @@ func normalizeReleaseDates(apps []models.App) []models.App {
 	return apps
 }
 
-// deduplicateReleases removes appstream releases when actual repo releases (GitHub/GitLab/Mozilla) exist
-// This prevents duplicate entries for the same version showing different dates
-func deduplicateReleases(apps []models.App) []models.App {
-	for i := range apps {
-		app := &apps[i]
+// releaseScheme returns the version scheme hint for an app's releases.
+// GNOME core modules still number development releases with odd minors (GTK, GLib, pre-40 apps).
+func releaseScheme(app models.App) versions.Scheme {
+	if app.SourceRepo != nil && strings.HasPrefix(app.SourceRepo.URL, "https://gitlab.gnome.org/GNOME/") {
+		return versions.GNOME
+	}
+	return versions.Semver
+}
+
+// sortReleases orders releases highest version first. Releases without a parseable version
+// go last, and ties are broken by date (newest first) and type so the order is deterministic.
+func sortReleases(releases []models.Release, scheme versions.Scheme) {
+	parsed := make(map[string]versions.Version, len(releases))
+	for _, release := range releases {
+		if v, ok := versions.ParseScheme(release.Version, scheme); ok {
+			parsed[release.Version] = v
+		}
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		a, okA := parsed[releases[i].Version]
+		b, okB := parsed[releases[j].Version]
+		if okA != okB {
+			return okA
+		}
+		if okA {
+			if cmp := a.Compare(b); cmp != 0 {
+				return cmp > 0
+			}
+		}
+		if !releases[i].Date.Equal(releases[j].Date) {
+			return releases[i].Date.After(releases[j].Date)
+		}
+		return releases[i].Type < releases[j].Type
+	})
+}
 
-		// Skip if no releases
-		if len(app.Releases) == 0 {
+// latestStableRelease returns the first stable release of a sorted release list,
+// falling back to the first release when every release is a pre-release
+func latestStableRelease(releases []models.Release, scheme versions.Scheme) models.Release {
+	for _, release := range releases {
+		if !release.Prerelease && !versions.IsPrerelease(release.Version, scheme) {
+			return release
+		}
+	}
+	return releases[0]
+}
+
+// defaultPrereleasePolicy excludes Bluefin OS pre-releases (beta images aren't announced);
+// every other source keeps its pre-releases, flagged on Release.Prerelease
+const defaultPrereleasePolicy = "bluefin-os=exclude"
+
+// parsePrereleasePolicy parses "source=include|exclude,..." into a map of source -> include.
+// Sources are release sources as returned by Release.Source (github, gitlab, appstream, tap, mozilla, bluefin-os).
+func parsePrereleasePolicy(spec string) (map[string]bool, error) {
+	policy := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
 			continue
 		}
 
-		// Check if there are any non-appstream releases
-		hasRepoReleases := false
+		source, action, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q (want source=include or source=exclude)", entry)
+		}
+
+		switch strings.TrimSpace(action) {
+		case "include":
+			policy[strings.TrimSpace(source)] = true
+		case "exclude":
+			policy[strings.TrimSpace(source)] = false
+		default:
+			return nil, fmt.Errorf("invalid action %q for %s (want include or exclude)", action, source)
+		}
+	}
+	return policy, nil
+}
+
+// filterPrereleases removes pre-releases from sources the policy excludes.
+// Sources not mentioned in the policy keep their pre-releases.
+func filterPrereleases(apps []models.App, policy map[string]bool) []models.App {
+	removed := 0
+	for i := range apps {
+		app := &apps[i]
+
+		filteredReleases := app.Releases[:0]
 		for _, release := range app.Releases {
-			if release.Type == "github-release" || release.Type == "gitlab-release" || release.Type == "mozilla-release" {
-				hasRepoReleases = true
-				break
+			if include, ok := policy[release.Source()]; release.Prerelease && ok && !include {
+				removed++
+				continue
 			}
+			filteredReleases = append(filteredReleases, release)
 		}
+		app.Releases = filteredReleases
+	}
 
-		// If we have repo releases, filter out appstream releases
-		if hasRepoReleases {
-			filteredReleases := []models.Release{}
-			removedCount := 0
-			for _, release := range app.Releases {
-				if release.Type == "appstream" {
-					removedCount++
-					continue
-				}
-				filteredReleases = append(filteredReleases, release)
-			}
-			app.Releases = filteredReleases
-			if removedCount > 0 {
-				log.Printf("Removed %d appstream release(s) from %s (has repo releases)", removedCount, app.ID)
+	log.Printf("Removed %d pre-release(s) excluded by policy", removed)
+	return apps
+}
+
+// sanitizeDescriptions reduces app and release descriptions to allowlisted HTML. Descriptions
+// come from release bodies, AppStream, feeds and scraped pages, and the site renders them as-is.
+func sanitizeDescriptions(apps []models.App) []models.App {
+	for i := range apps {
+		app := &apps[i]
+		app.Description = markdown.Sanitize(app.Description)
+		for j := range app.Releases {
+			app.Releases[j].Description = markdown.Sanitize(app.Releases[j].Description)
+		}
+	}
+	return apps
+}
+
+// summarizeReleases stores a plain-text rendering and an excerpt of each release description
+func summarizeReleases(apps []models.App, excerptLength int) []models.App {
+	for i := range apps {
+		for j := range apps[i].Releases {
+			release := &apps[i].Releases[j]
+			release.PlainText = markdown.PlainText(release.Description)
+			release.Excerpt = markdown.Excerpt(release.Description, excerptLength)
+		}
+	}
+	return apps
+}
+
+// classifyReleases tags each app's releases from their notes and version changes.
+// Releases must already be sorted newest first.
+func classifyReleases(apps []models.App) []models.App {
+	tagged := 0
+	for i := range apps {
+		app := &apps[i]
+		classify.Releases(app.Releases, releaseScheme(*app))
+		for _, release := range app.Releases {
+			if len(release.Tags) > 0 {
+				tagged++
 			}
 		}
 	}
+
+	log.Printf("Classified releases (%d tagged)", tagged)
 	return apps
 }
 
 func main() {
 	// Parse command-line flags
 	legacyMode := flag.Bool("legacy", false, "Use legacy mode (fetch recently updated apps instead of Bluefin list)")
+	lagVersions := flag.Int("lag-versions", bluefin.DefaultLivecheckThresholds.VersionsBehind, "Flag Homebrew packages this many upstream releases behind")
+	lagDays := flag.Int("lag-days", bluefin.DefaultLivecheckThresholds.DaysBehind, "Flag Homebrew packages missing an upstream release older than this many days")
+	tagPattern := flag.String("tag-pattern", versions.DefaultTagPattern, "Regular expression for version-like tags used when a repo publishes no releases")
+	gitlabPages := flag.Int("gitlab-pages", gitlab.MaxPages, "Maximum pages followed per GitLab releases/tags request")
+	prereleases := flag.String("prereleases", defaultPrereleasePolicy, "Per-source pre-release policy (e.g., \"github=include,bluefin-os=exclude\")")
+	excerptLength := flag.Int("excerpt-length", markdown.DefaultExcerptLength, "Maximum length of release note excerpts, in characters")
+	osvDump := flag.String("osv-dump", "", "OSV vulnerability dump (directory of JSON records, zip archive or JSON file) to check shipped versions against")
+	osvAPI := flag.Bool("osv-api", false, "Also query the OSV API for vulnerabilities of shipped versions")
 	flag.Parse()
 
+	if err := versions.SetTagPattern(*tagPattern); err != nil {
+		log.Fatalf("Invalid -tag-pattern: %v", err)
+	}
+	gitlab.MaxPages = *gitlabPages
+	prereleasePolicy, err := parsePrereleasePolicy(*prereleases)
+	if err != nil {
+		log.Fatalf("Invalid -prereleases: %v", err)
+	}
+
 	startTime := time.Now()
 
 	log.Printf("Bluefin Releases Pipeline v%s", version)
@@ -212,6 +347,13 @@ func main() {
 	githubDuration := time.Since(githubStart)
 	log.Printf("GitHub enrichment complete in %s", githubDuration)
 
+	// Step 5.1: Compare Homebrew stable versions against upstream GitHub releases and tags (before merging)
+	log.Println("Comparing Homebrew versions against upstream releases...")
+	enrichedApps = bluefin.CheckHomebrewUpstream(enrichedApps, bluefin.LivecheckThresholds{
+		VersionsBehind: *lagVersions,
+		DaysBehind:     *lagDays,
+	})
+
 	// Step 5.5: Enrich with GitLab releases (from actual source repos)
 	log.Println("Enriching with GitLab releases from source repositories...")
 	gitlabStart := time.Now()
@@ -219,6 +361,27 @@ func main() {
 	gitlabDuration := time.Since(gitlabStart)
 	log.Printf("GitLab enrichment complete in %s", gitlabDuration)
 
+	// Step 5.55: Enrich with Gitea/Forgejo releases (Codeberg and self-hosted instances)
+	log.Println("Enriching with Gitea/Forgejo releases...")
+	giteaStart := time.Now()
+	enrichedApps = gitea.EnrichWithGiteaReleases(enrichedApps)
+	giteaDuration := time.Since(giteaStart)
+	log.Printf("Gitea enrichment complete in %s", giteaDuration)
+
+	// Step 5.56: Enrich SourceHut and other git remotes with version tags
+	log.Println("Enriching SourceHut/git repos with version tags...")
+	gitTagsStart := time.Now()
+	enrichedApps = gittags.EnrichWithGitTags(enrichedApps)
+	gitTagsDuration := time.Since(gitTagsStart)
+	log.Printf("Git tag enrichment complete in %s", gitTagsDuration)
+
+	// Step 5.57: Enrich apps that declare a release feed in source-overrides.json
+	log.Println("Enriching apps with release feeds...")
+	feedStart := time.Now()
+	enrichedApps = rss.EnrichWithFeeds(enrichedApps)
+	feedDuration := time.Since(feedStart)
+	log.Printf("Feed enrichment complete in %s", feedDuration)
+
 	// Step 5.6: Enrich with Mozilla release notes (Firefox and Thunderbird)
 	log.Println("Enriching Mozilla products with release notes...")
 	mozillaStart := time.Now()
@@ -226,20 +389,53 @@ func main() {
 	mozillaDuration := time.Since(mozillaStart)
 	log.Printf("Mozilla enrichment complete in %s", mozillaDuration)
 
-	// Step 5.7: Deduplicate releases (remove appstream releases when actual repo releases exist)
-	log.Println("Deduplicating releases (removing appstream releases when repo releases exist)...")
-	dedupeStart := time.Now()
-	enrichedApps = deduplicateReleases(enrichedApps)
-	dedupeDuration := time.Since(dedupeStart)
-	log.Printf("Release deduplication complete in %s", dedupeDuration)
-
-	// Step 5.8: Normalize top-level fields from latest release
-	log.Println("Normalizing top-level date fields from latest releases...")
+	// Step 5.65: Drop pre-releases from sources whose policy excludes them
+	enrichedApps = filterPrereleases(enrichedApps, prereleasePolicy)
+
+	// Step 5.7: Merge releases of the same version reported by several sources
+	log.Println("Merging releases across sources by version...")
+	mergeStart := time.Now()
+	enrichedApps = merge.MergeReleases(enrichedApps)
+	mergeDuration := time.Since(mergeStart)
+	log.Printf("Release merge complete in %s", mergeDuration)
+
+	// Step 5.72: Read release notes from project websites declared in internal/notespage/pages.json
+	log.Println("Reading release notes pages from project websites...")
+	notesPageStart := time.Now()
+	enrichedApps = notespage.EnrichWithNotesPages(enrichedApps)
+	notesPageDuration := time.Since(notesPageStart)
+	log.Printf("Release notes page enrichment complete in %s", notesPageDuration)
+
+	// Step 5.75: Fill in missing release notes from CHANGELOG/NEWS files in source repos
+	log.Println("Filling missing release notes from changelog files...")
+	changelogStart := time.Now()
+	enrichedApps = changelog.EnrichWithChangelogs(enrichedApps)
+	changelogDuration := time.Since(changelogStart)
+	log.Printf("Changelog enrichment complete in %s", changelogDuration)
+
+	// Step 5.77: Sanitize descriptions from every source before they are rendered as HTML
+	enrichedApps = sanitizeDescriptions(enrichedApps)
+
+	// Step 5.78: Plain-text renderings and excerpts of release notes for feeds and other consumers
+	enrichedApps = summarizeReleases(enrichedApps, *excerptLength)
+
+	// Step 5.8: Sort releases by version and normalize top-level fields from the latest stable release
+	log.Println("Sorting releases and normalizing top-level fields from latest stable releases...")
 	normalizeStart := time.Now()
 	enrichedApps = normalizeReleaseDates(enrichedApps)
 	normalizeDuration := time.Since(normalizeStart)
 	log.Printf("Date normalization complete in %s", normalizeDuration)
 
+	// Step 5.82: Flag shipped versions with known vulnerabilities and the releases that fix them
+	log.Println("Checking shipped versions against OSV vulnerability data...")
+	osvStart := time.Now()
+	enrichedApps = osv.EnrichWithVulnerabilities(enrichedApps, *osvDump, *osvAPI)
+	osvDuration := time.Since(osvStart)
+	log.Printf("Vulnerability check complete in %s", osvDuration)
+
+	// Step 5.85: Tag releases as security, breaking, feature, bugfix-only or translation-only
+	enrichedApps = classifyReleases(enrichedApps)
+
 	// Step 5: Sort by update date (Flatpak apps have updatedAt, Homebrew may not)
 	// For now, just use the order they come in (Flatpak first, then Homebrew)
 	// Future: could sort by latest release date
@@ -247,10 +443,15 @@ func main() {
 	// Step 6: Collect statistics
 	appsWithGitHubRepo := 0
 	appsWithGitLabRepo := 0
+	appsWithGiteaRepo := 0
 	appsWithChangelogs := 0
 	totalReleases := 0
 	flatpakCount := 0
 	homebrewCount := 0
+	homebrewOutdated := 0
+	homebrewNoArm64 := 0
+	releasesWithoutAssets := 0
+	appsVulnerable := 0
 	osCount := 0
 
 	for _, app := range enrichedApps {
@@ -259,16 +460,32 @@ func main() {
 				appsWithGitHubRepo++
 			} else if app.SourceRepo.Type == "gitlab" {
 				appsWithGitLabRepo++
+			} else if app.SourceRepo.Type == "gitea" {
+				appsWithGiteaRepo++
 			}
 		}
 		if len(app.Releases) > 0 {
 			appsWithChangelogs++
 			totalReleases += len(app.Releases)
 		}
+		for _, release := range app.Releases {
+			if (release.Type == "github-release" || release.Type == "gitlab-release" || release.Type == "gitea-release") && len(release.Assets) == 0 {
+				releasesWithoutAssets++
+			}
+		}
+		if len(app.Vulnerabilities) > 0 {
+			appsVulnerable++
+		}
 		if app.PackageType == "flatpak" {
 			flatpakCount++
 		} else if app.PackageType == "homebrew" {
 			homebrewCount++
+			if app.UpstreamStatus != nil && app.UpstreamStatus.Outdated {
+				homebrewOutdated++
+			}
+			if app.HomebrewInfo != nil && app.HomebrewInfo.Bottles != nil && !app.HomebrewInfo.Bottles.Arm64Linux {
+				homebrewNoArm64++
+			}
 		} else if app.PackageType == "os" {
 			osCount++
 		}
@@ -276,8 +493,13 @@ func main() {
 
 	log.Printf("Apps with GitHub repos: %d", appsWithGitHubRepo)
 	log.Printf("Apps with GitLab repos: %d", appsWithGitLabRepo)
+	log.Printf("Apps with Gitea/Forgejo repos: %d", appsWithGiteaRepo)
 	log.Printf("Apps with changelogs: %d", appsWithChangelogs)
 	log.Printf("Total releases: %d", totalReleases)
+	log.Printf("Homebrew packages lagging upstream: %d", homebrewOutdated)
+	log.Printf("Homebrew packages without arm64 Linux bottles: %d", homebrewNoArm64)
+	log.Printf("GitHub/GitLab/Gitea releases without assets: %d", releasesWithoutAssets)
+	log.Printf("Apps with known vulnerabilities: %d", appsVulnerable)
 
 	// Step 7: Build output structure
 	buildDuration := time.Since(startTime)
@@ -288,19 +510,30 @@ func main() {
 			GeneratedBy:   fmt.Sprintf("bluefin-releases v%s", version),
 			BuildDuration: buildDuration.String(),
 			Stats: models.Stats{
-				AppsTotal:          len(enrichedApps),
-				AppsWithGitHubRepo: appsWithGitHubRepo,
-				AppsWithGitLabRepo: appsWithGitLabRepo,
-				AppsWithChangelogs: appsWithChangelogs,
-				TotalReleases:      totalReleases,
+				AppsTotal:             len(enrichedApps),
+				AppsWithGitHubRepo:    appsWithGitHubRepo,
+				AppsWithGitLabRepo:    appsWithGitLabRepo,
+				AppsWithGiteaRepo:     appsWithGiteaRepo,
+				AppsWithChangelogs:    appsWithChangelogs,
+				TotalReleases:         totalReleases,
+				HomebrewOutdated:      homebrewOutdated,
+				HomebrewNoArm64Bottle: homebrewNoArm64,
+				ReleasesWithoutAssets: releasesWithoutAssets,
+				AppsVulnerable:        appsVulnerable,
 			},
 			Performance: models.Performance{
-				FlathubFetchDuration: flathubDuration.String(),
-				DetailsFetchDuration: flathubDuration.String(), // Combined in FetchAllApps
-				GitHubFetchDuration:  githubDuration.String(),
-				GitLabFetchDuration:  gitlabDuration.String(),
-				MozillaFetchDuration: mozillaDuration.String(),
-				OutputDuration:       "0s", // Will be updated
+				FlathubFetchDuration:   flathubDuration.String(),
+				DetailsFetchDuration:   flathubDuration.String(), // Combined in FetchAllApps
+				GitHubFetchDuration:    githubDuration.String(),
+				GitLabFetchDuration:    gitlabDuration.String(),
+				GiteaFetchDuration:     giteaDuration.String(),
+				GitTagsFetchDuration:   gitTagsDuration.String(),
+				FeedFetchDuration:      feedDuration.String(),
+				MozillaFetchDuration:   mozillaDuration.String(),
+				NotesPageFetchDuration: notesPageDuration.String(),
+				ChangelogFetchDuration: changelogDuration.String(),
+				OSVCheckDuration:       osvDuration.String(),
+				OutputDuration:         "0s", // Will be updated
 			},
 		},
 		Apps: enrichedApps,
@@ -331,8 +564,12 @@ func main() {
 		"os_count":            osCount,
 		"apps_with_github":    appsWithGitHubRepo,
 		"apps_with_gitlab":    appsWithGitLabRepo,
+		"apps_with_gitea":     appsWithGiteaRepo,
 		"apps_with_changelog": appsWithChangelogs,
 		"total_releases":      totalReleases,
+		"homebrew_outdated":   homebrewOutdated,
+		"homebrew_no_arm64":   homebrewNoArm64,
+		"releases_no_assets":  releasesWithoutAssets,
 	}
 	summaryJSON, _ := json.MarshalIndent(summary, "", "  ")
 	fmt.Println(string(summaryJSON))
@@ -6,7 +6,8 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"regexp"
+
+	"github.com/castrojo/bluefin-releases/internal/brewfile"
 )
 
 const (
@@ -132,35 +133,16 @@ func fetchRawFile(owner, repo, branch, path string) ([]byte, error) {
 }
 
 // parseFlatpakBrewfile parses a Brewfile and extracts Flatpak app IDs
-// Matches lines like: flatpak "org.gnome.Calculator"
+// Matches active lines like: flatpak "org.gnome.Calculator" (commented-out entries are skipped)
 func parseFlatpakBrewfile(content []byte) []string {
 	var appIDs []string
 
-	// Regex pattern: flatpak "app.id.here"
-	re := regexp.MustCompile(`flatpak\s+"([^"]+)"`)
-
-	matches := re.FindAllSubmatch(content, -1)
-	for _, match := range matches {
-		if len(match) >= 2 {
-			appID := string(match[1])
-			appIDs = append(appIDs, appID)
+	for _, entry := range brewfile.Filter(brewfile.Parse(content), brewfile.KindFlatpak) {
+		if entry.Remote != brewfile.DefaultFlatpakRemote {
+			log.Printf("  Note: %s is installed from remote %q, Flathub metadata may be missing", entry.Name, entry.Remote)
 		}
+		appIDs = append(appIDs, entry.Name)
 	}
 
 	return appIDs
 }
-
-// deduplicate removes duplicate strings from a slice
-func deduplicate(items []string) []string {
-	seen := make(map[string]bool)
-	result := []string{}
-
-	for _, item := range items {
-		if !seen[item] {
-			seen[item] = true
-			result = append(result, item)
-		}
-	}
-
-	return result
-}
@@ -7,10 +7,12 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/brewfile"
 	"github.com/castrojo/bluefin-releases/internal/models"
 )
 
@@ -48,7 +50,36 @@ type Bottle struct {
 }
 
 type BottleStable struct {
-	Files map[string]interface{} `json:"files"`
+	Rebuild int                   `json:"rebuild"`
+	RootURL string                `json:"root_url"`
+	Files   map[string]BottleFile `json:"files"` // Keyed by bottle tag (e.g., "x86_64_linux", "arm64_sonoma", "all")
+}
+
+type BottleFile struct {
+	Cellar string `json:"cellar"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Linux bottle tags for the architectures Bluefin runs on
+const (
+	BottleTagX86Linux   = "x86_64_linux"
+	BottleTagArm64Linux = "arm64_linux"
+	BottleTagAll        = "all" // Architecture-independent bottle
+)
+
+// HomebrewCask represents cask metadata from Homebrew API
+type HomebrewCask struct {
+	Token      string   `json:"token"`
+	FullToken  string   `json:"full_token"`
+	Tap        string   `json:"tap"`
+	Name       []string `json:"name"`
+	Desc       string   `json:"desc"`
+	Homepage   string   `json:"homepage"`
+	URL        string   `json:"url"`
+	Version    string   `json:"version"`
+	Deprecated bool     `json:"deprecated"`
+	Disabled   bool     `json:"disabled"`
 }
 
 // FetchHomebrewPackages fetches Homebrew packages from Brewfiles and enriches with metadata
@@ -56,39 +87,51 @@ type BottleStable struct {
 func FetchHomebrewPackages() ([]models.App, error) {
 	log.Println("Fetching Bluefin Homebrew packages...")
 
-	// Step 1: Parse Brewfiles to get package names
-	packageNames, err := FetchHomebrewList()
+	// Step 1: Parse Brewfiles to get package names and their Brewfile grouping
+	packageInfos, err := FetchHomebrewListWithBrewfiles()
 	if err != nil {
 		return nil, fmt.Errorf("fetch homebrew list: %w", err)
 	}
 
-	log.Printf("Fetching metadata for %d Homebrew packages...", len(packageNames))
+	log.Printf("Fetching metadata for %d Homebrew packages...", len(packageInfos))
 
 	// Step 2: Fetch metadata for each package (with concurrency)
-	apps := make([]models.App, 0, len(packageNames))
+	apps := make([]models.App, 0, len(packageInfos))
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, 10) // Limit to 10 concurrent requests
 
-	for _, pkgName := range packageNames {
+	for _, pkgInfo := range packageInfos {
 		wg.Add(1)
-		go func(name string) {
+		go func(info HomebrewPackageInfo) {
 			defer wg.Done()
 			semaphore <- struct{}{}        // Acquire semaphore
 			defer func() { <-semaphore }() // Release semaphore
 
-			app, err := fetchHomebrewPackageMetadata(name)
+			var app *models.App
+			var err error
+			if info.Cask {
+				app, err = fetchHomebrewCaskMetadata(info.Name)
+			} else {
+				app, err = fetchHomebrewPackageMetadata(info.Name)
+			}
 			if err != nil {
-				log.Printf("⚠️  Failed to fetch metadata for %s: %v", name, err)
+				log.Printf("⚠️  Failed to fetch metadata for %s: %v", info.Name, err)
 				return
 			}
 
 			if app != nil {
+				// Record which Brewfile the package came from
+				app.Brewfile = info.Brewfile
+				if info.Brewfile == "experimental" {
+					app.Experimental = true
+				}
+
 				mu.Lock()
 				apps = append(apps, *app)
 				mu.Unlock()
 			}
-		}(pkgName)
+		}(pkgInfo)
 	}
 
 	wg.Wait()
@@ -104,7 +147,7 @@ func fetchHomebrewPackageMetadata(packageName string) (*models.App, error) {
 	if strings.Contains(packageName, "/") {
 		// For custom tap packages, we'll create a minimal entry
 		// since they're not in the main Homebrew API
-		return createMinimalHomebrewApp(packageName), nil
+		return createMinimalHomebrewApp(packageName, false), nil
 	}
 
 	// Fetch from Homebrew API
@@ -124,7 +167,7 @@ func fetchHomebrewPackageMetadata(packageName string) (*models.App, error) {
 
 	if resp.StatusCode == http.StatusNotFound {
 		// Package not found in homebrew-core, treat as custom tap
-		return createMinimalHomebrewApp(packageName), nil
+		return createMinimalHomebrewApp(packageName, false), nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -157,16 +200,97 @@ func fetchHomebrewPackageMetadata(packageName string) (*models.App, error) {
 	return convertHomebrewFormulaToApp(formula), nil
 }
 
+// fetchHomebrewCaskMetadata fetches metadata for a single Homebrew cask
+func fetchHomebrewCaskMetadata(caskName string) (*models.App, error) {
+	// Custom tap casks are not in the main Homebrew API
+	if strings.Contains(caskName, "/") {
+		return createMinimalHomebrewApp(caskName, true), nil
+	}
+
+	url := fmt.Sprintf("https://formulae.brew.sh/api/cask/%s.json", caskName)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return createMinimalHomebrewApp(caskName, true), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var cask HomebrewCask
+	if err := json.NewDecoder(resp.Body).Decode(&cask); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if cask.Deprecated || cask.Disabled {
+		log.Printf("  Skipping deprecated/disabled cask: %s", caskName)
+		return nil, nil
+	}
+
+	return convertHomebrewCaskToApp(cask), nil
+}
+
+// convertHomebrewCaskToApp converts a Homebrew cask to our App model
+func convertHomebrewCaskToApp(cask HomebrewCask) *models.App {
+	name := cask.Token
+	if len(cask.Name) > 0 && cask.Name[0] != "" {
+		name = cask.Name[0]
+	}
+
+	summary := cask.Desc
+	if summary == "" {
+		summary = fmt.Sprintf("Homebrew cask: %s", name)
+	}
+
+	app := &models.App{
+		ID:          homebrewAppID(cask.Token, true),
+		Name:        name,
+		Summary:     summary,
+		Description: cask.Desc,
+		Version:     cask.Version,
+		PackageType: "homebrew",
+		FetchedAt:   time.Now(),
+		HomebrewInfo: &models.HomebrewInfo{
+			Formula:  cask.Token,
+			FullName: cask.FullToken,
+			Tap:      cask.Tap,
+			Homepage: cask.Homepage,
+			Versions: []string{cask.Version},
+		},
+	}
+
+	// Extract GitHub URL for source repo
+	if sourceRepo := extractGitHubRepoFromURL(cask.URL); sourceRepo != nil {
+		app.SourceRepo = sourceRepo
+	} else if sourceRepo := extractGitHubRepoFromURL(cask.Homepage); sourceRepo != nil {
+		app.SourceRepo = sourceRepo
+	}
+
+	return app
+}
+
 // isLinuxCompatible checks if a formula has Linux bottles
 func isLinuxCompatible(formula HomebrewFormula) bool {
-	if formula.Bottle == nil {
+	if formula.Bottle == nil || len(formula.Bottle.Stable.Files) == 0 {
 		// No bottles means source-only (still might be Linux compatible)
 		return true
 	}
 
-	// Check for Linux-specific bottles
-	for filename := range formula.Bottle.Stable.Files {
-		if strings.Contains(filename, "linux") {
+	// Check for Linux-specific or architecture-independent bottles
+	for tag := range formula.Bottle.Stable.Files {
+		if strings.Contains(tag, "linux") || tag == BottleTagAll {
 			return true
 		}
 	}
@@ -174,13 +298,42 @@ func isLinuxCompatible(formula HomebrewFormula) bool {
 	return false
 }
 
+// extractBottleInfo records which bottles a formula ships and which Linux
+// architectures would have to build it from source
+func extractBottleInfo(formula HomebrewFormula) *models.BottleInfo {
+	info := &models.BottleInfo{}
+
+	if formula.Bottle != nil {
+		info.Rebuild = formula.Bottle.Stable.Rebuild
+		for tag := range formula.Bottle.Stable.Files {
+			info.Tags = append(info.Tags, tag)
+		}
+		sort.Strings(info.Tags)
+
+		_, all := formula.Bottle.Stable.Files[BottleTagAll]
+		_, x86 := formula.Bottle.Stable.Files[BottleTagX86Linux]
+		_, arm64 := formula.Bottle.Stable.Files[BottleTagArm64Linux]
+		info.X86Linux = x86 || all
+		info.Arm64Linux = arm64 || all
+	}
+
+	if !info.X86Linux {
+		info.BuildsFromSource = append(info.BuildsFromSource, "x86_64")
+	}
+	if !info.Arm64Linux {
+		info.BuildsFromSource = append(info.BuildsFromSource, "aarch64")
+	}
+
+	return info
+}
+
 // convertHomebrewFormulaToApp converts a Homebrew formula to our App model
 func convertHomebrewFormulaToApp(formula HomebrewFormula) *models.App {
 	// Clean up the name - remove "homebrew-" prefix if present
 	cleanName := strings.TrimPrefix(formula.Name, "homebrew-")
 
 	app := &models.App{
-		ID:          fmt.Sprintf("homebrew-%s", formula.Name),
+		ID:          homebrewAppID(formula.Name, false),
 		Name:        cleanName,
 		Summary:     formula.Desc,
 		Description: formula.Desc,
@@ -193,6 +346,7 @@ func convertHomebrewFormulaToApp(formula HomebrewFormula) *models.App {
 			Tap:      formula.Tap,
 			Homepage: formula.Homepage,
 			Versions: []string{formula.Versions.Stable},
+			Bottles:  extractBottleInfo(formula),
 		},
 	}
 
@@ -231,8 +385,18 @@ func extractGitHubRepoFromURL(urlStr string) *models.SourceRepo {
 	return nil
 }
 
+// homebrewAppID returns the app ID of a formula or cask; casks get their own prefix since a
+// formula and a cask may share a token (e.g., "homebrew-docker" and "homebrew-cask-docker")
+func homebrewAppID(packageName string, cask bool) string {
+	prefix := "homebrew-"
+	if cask {
+		prefix = "homebrew-cask-"
+	}
+	return prefix + strings.ReplaceAll(packageName, "/", "-")
+}
+
 // createMinimalHomebrewApp creates a minimal App entry for custom tap packages
-func createMinimalHomebrewApp(packageName string) *models.App {
+func createMinimalHomebrewApp(packageName string, cask bool) *models.App {
 	// Clean up the name - remove "homebrew-" prefix if present
 	cleanName := strings.TrimPrefix(packageName, "homebrew-")
 	// For tap packages with "/", use the package name after the "/"
@@ -241,10 +405,15 @@ func createMinimalHomebrewApp(packageName string) *models.App {
 		cleanName = parts[len(parts)-1]
 	}
 
+	kind := "package"
+	if cask {
+		kind = "cask"
+	}
+
 	return &models.App{
-		ID:          fmt.Sprintf("homebrew-%s", strings.ReplaceAll(packageName, "/", "-")),
+		ID:          homebrewAppID(packageName, cask),
 		Name:        cleanName,
-		Summary:     fmt.Sprintf("Homebrew package: %s", cleanName),
+		Summary:     fmt.Sprintf("Homebrew %s: %s", kind, cleanName),
 		PackageType: "homebrew",
 		FetchedAt:   time.Now(),
 		HomebrewInfo: &models.HomebrewInfo{
@@ -253,63 +422,101 @@ func createMinimalHomebrewApp(packageName string) *models.App {
 	}
 }
 
+// HomebrewPackageInfo contains a Homebrew package name and the Brewfile it was listed in
+type HomebrewPackageInfo struct {
+	Name     string // Fully-qualified for tap packages (e.g., "ublue-os/tap/bluefin-cli")
+	Brewfile string // "cli", "ai-tools", "k8s-tools", "ide", "fonts", "artwork", or "experimental"
+	Cask     bool   // True for cask entries, false for formulae
+}
+
+// homebrewBrewfile maps a Brewfile path to its group name
+type homebrewBrewfile struct {
+	Path  string
+	Group string
+}
+
+// homebrewBrewfiles lists the Bluefin Brewfiles containing Homebrew package definitions.
+// Order matters: a package listed in several Brewfiles is attributed to the first one.
+var homebrewBrewfiles = []homebrewBrewfile{
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/cli.Brewfile", Group: "cli"},
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/ai-tools.Brewfile", Group: "ai-tools"},
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/k8s-tools.Brewfile", Group: "k8s-tools"},
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/ide.Brewfile", Group: "ide"},
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/fonts.Brewfile", Group: "fonts"},
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/artwork.Brewfile", Group: "artwork"},
+	{Path: "system_files/shared/usr/share/ublue-os/homebrew/experimental.Brewfile", Group: "experimental"},
+}
+
 // FetchHomebrewList fetches the list of Homebrew packages that Bluefin includes
 // by parsing the Brewfiles from projectbluefin/common repository.
 // Returns a slice of Homebrew package names (e.g., "bat", "gh").
 // Supports GITHUB_TOKEN environment variable for API rate limits.
 func FetchHomebrewList() ([]string, error) {
-	log.Println("Fetching Bluefin Homebrew package list from Brewfiles...")
-
-	var allPackages []string
+	packageInfos, err := FetchHomebrewListWithBrewfiles()
+	if err != nil {
+		return nil, err
+	}
 
-	// List of Brewfiles containing Homebrew package definitions
-	brewfiles := []string{
-		"system_files/shared/usr/share/ublue-os/homebrew/cli.Brewfile",
-		"system_files/shared/usr/share/ublue-os/homebrew/ai-tools.Brewfile",
-		"system_files/shared/usr/share/ublue-os/homebrew/k8s-tools.Brewfile",
-		"system_files/shared/usr/share/ublue-os/homebrew/ide.Brewfile",
-		// Skip fonts, artwork, and experimental for now (too many, less relevant for release tracking)
+	// Extract just the package names for backward compatibility
+	packages := make([]string, len(packageInfos))
+	for i, info := range packageInfos {
+		packages[i] = info.Name
 	}
 
-	for _, brewfile := range brewfiles {
-		log.Printf("  Fetching %s...", brewfile)
+	return packages, nil
+}
+
+// FetchHomebrewListWithBrewfiles fetches the list of Homebrew packages with their Brewfile grouping
+// Returns a slice of HomebrewPackageInfo containing package names and their Brewfile (cli, fonts, ...).
+func FetchHomebrewListWithBrewfiles() ([]HomebrewPackageInfo, error) {
+	log.Println("Fetching Bluefin Homebrew package list from Brewfiles...")
 
-		content, err := fetchRawFile(BluefinCommonOwner, BluefinCommonRepo, BluefinCommonBranch, brewfile)
+	var allPackageInfos []HomebrewPackageInfo
+	seen := make(map[string]bool)
+	countByBrewfile := make(map[string]int)
+
+	for _, bf := range homebrewBrewfiles {
+		log.Printf("  Fetching %s (%s packages)...", bf.Path, bf.Group)
+
+		content, err := fetchRawFile(BluefinCommonOwner, BluefinCommonRepo, BluefinCommonBranch, bf.Path)
 		if err != nil {
-			log.Printf("⚠️  Failed to fetch %s: %v", brewfile, err)
+			log.Printf("⚠️  Failed to fetch %s: %v", bf.Path, err)
 			continue // Skip this file, but continue with others
 		}
 
 		packages := parseHomebrewBrewfile(content)
-		log.Printf("  Found %d Homebrew packages in %s", len(packages), brewfile)
-
-		allPackages = append(allPackages, packages...)
+		log.Printf("  Found %d Homebrew packages in %s", len(packages), bf.Path)
+
+		for _, entry := range packages {
+			// Deduplicate packages (first Brewfile wins); a formula and a cask may share a name
+			name := entry.FullName()
+			key := string(entry.Kind) + ":" + name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			allPackageInfos = append(allPackageInfos, HomebrewPackageInfo{
+				Name:     name,
+				Brewfile: bf.Group,
+				Cask:     entry.Kind == brewfile.KindCask,
+			})
+			countByBrewfile[bf.Group]++
+		}
 	}
 
-	// Deduplicate package names
-	allPackages = deduplicate(allPackages)
-
-	log.Printf("✅ Total Homebrew packages: %d", len(allPackages))
-	return allPackages, nil
-}
-
-// parseHomebrewBrewfile parses a Brewfile and extracts Homebrew package names
-// Matches lines like: brew "package-name"
-// Ignores tap lines like: tap "owner/repo"
-func parseHomebrewBrewfile(content []byte) []string {
-	var packages []string
-
-	// Regex pattern: brew "package-name"
-	// Note: We ignore tap lines, only extract brew package names
-	re := regexp.MustCompile(`brew\s+"([^"]+)"`)
-
-	matches := re.FindAllSubmatch(content, -1)
-	for _, match := range matches {
-		if len(match) >= 2 {
-			packageName := string(match[1])
-			packages = append(packages, packageName)
+	log.Printf("✅ Total Homebrew packages: %d", len(allPackageInfos))
+	for _, bf := range homebrewBrewfiles {
+		if count := countByBrewfile[bf.Group]; count > 0 {
+			log.Printf("  %s: %d", bf.Group, count)
 		}
 	}
+	return allPackageInfos, nil
+}
 
-	return packages
+// parseHomebrewBrewfile parses a Brewfile and returns its active Homebrew entries
+// Matches lines like: brew "package-name", brew "owner/tap/name", or cask "font-name"
+// Ignores tap lines and commented-out entries
+func parseHomebrewBrewfile(content []byte) []brewfile.Entry {
+	return brewfile.Filter(brewfile.Parse(content), brewfile.KindBrew, brewfile.KindCask)
 }
@@ -13,8 +13,13 @@ import (
 	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
 )
 
+// TapHistoryDepth is the maximum number of commits inspected per formula/cask file
+// when reconstructing a tap package's version history
+const TapHistoryDepth = 15
+
 // TapConfig defines a Homebrew tap repository to fetch from
 type TapConfig struct {
 	Owner        string
@@ -30,6 +35,18 @@ type GitHubContentItem struct {
 	DownloadURL string `json:"download_url"`
 }
 
+// GitHubCommit represents a commit from the GitHub commits API
+type GitHubCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message   string `json:"message"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
 // FormulaMetadata holds parsed metadata from .rb files
 type FormulaMetadata struct {
 	Description string
@@ -156,21 +173,10 @@ func fetchTapDirectory(owner, repo, directory, pkgType string, experimental bool
 // parseTapPackage fetches and parses a .rb file to extract metadata
 func parseTapPackage(owner, repo, directory, filename, pkgName, pkgType string, experimental bool) (models.App, error) {
 	// Fetch raw .rb file
-	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/main/%s/%s", owner, repo, directory, filename)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return models.App{}, fmt.Errorf("fetch file: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return models.App{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	content, err := io.ReadAll(resp.Body)
+	path := fmt.Sprintf("%s/%s", directory, filename)
+	content, err := fetchTapFile(owner, repo, "main", path)
 	if err != nil {
-		return models.App{}, fmt.Errorf("read file: %w", err)
+		return models.App{}, err
 	}
 
 	// Parse metadata from Ruby file
@@ -181,7 +187,7 @@ func parseTapPackage(owner, repo, directory, filename, pkgName, pkgType string,
 	fullName := fmt.Sprintf("%s/%s", tapName, pkgName)
 
 	app := models.App{
-		ID:           fmt.Sprintf("homebrew-%s", strings.ReplaceAll(fullName, "/", "-")),
+		ID:           homebrewAppID(fullName, pkgType == "cask"),
 		Name:         pkgName,
 		Summary:      metadata.Description,
 		Description:  metadata.Description,
@@ -202,6 +208,16 @@ func parseTapPackage(owner, repo, directory, filename, pkgName, pkgType string,
 		app.Summary = fmt.Sprintf("Homebrew %s: %s", pkgType, pkgName)
 	}
 
+	// Reconstruct version history from the tap's git history (needs GITHUB_TOKEN for API limits)
+	if os.Getenv("GITHUB_TOKEN") != "" {
+		history, err := fetchTapVersionHistory(owner, repo, path, pkgName)
+		if err != nil {
+			log.Printf("⚠️  Failed to fetch version history for %s: %v", fullName, err)
+		} else {
+			app.Releases = history
+		}
+	}
+
 	// Extract GitHub repo if present
 	if metadata.GitHubRepo != "" {
 		parts := strings.Split(metadata.GitHubRepo, "/")
@@ -218,6 +234,132 @@ func parseTapPackage(owner, repo, directory, filename, pkgName, pkgType string,
 	return app, nil
 }
 
+// fetchTapFile fetches a raw file from a tap repository at the given ref (branch or commit SHA)
+func fetchTapFile(owner, repo, ref, path string) ([]byte, error) {
+	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", owner, repo, ref, path)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	return content, nil
+}
+
+// fetchTapVersionHistory reconstructs a dated version history for a tap formula/cask
+// by walking the commits that touched its .rb file and parsing the version at each one.
+// Returns releases of type "tap-update", newest first.
+func fetchTapVersionHistory(owner, repo, path, pkgName string) ([]models.Release, error) {
+	commits, err := fetchFileCommits(owner, repo, path, TapHistoryDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the version at each commit (commits are returned newest first)
+	versions := make([]string, len(commits))
+	for i, commit := range commits {
+		content, err := fetchTapFile(owner, repo, commit.SHA, path)
+		if err != nil {
+			log.Printf("  ⚠️  Failed to fetch %s at %s: %v", path, commit.SHA[:7], err)
+			continue
+		}
+		versions[i] = parseRubyFormula(string(content)).Version
+	}
+
+	return buildTapVersionHistory(commits, versions, pkgName, len(commits) < TapHistoryDepth), nil
+}
+
+// buildTapVersionHistory turns per-commit versions (newest first) into releases dated
+// by the commit that introduced each version. When complete is false the oldest
+// version's introducing commit lies outside the inspected window, so its date is
+// unknown and it is omitted.
+func buildTapVersionHistory(commits []GitHubCommit, versions []string, pkgName string, complete bool) []models.Release {
+	var releases []models.Release
+
+	// Walk from newest to oldest: a version is introduced by the oldest consecutive
+	// commit that still has it
+	for i := 0; i < len(commits); i++ {
+		tagVersion := versions[i]
+		if tagVersion == "" {
+			continue
+		}
+
+		// Skip forward over older commits with the same (or unparseable) version
+		j := i
+		for j+1 < len(commits) && (versions[j+1] == tagVersion || versions[j+1] == "") {
+			j++
+		}
+
+		if j == len(commits)-1 && !complete {
+			break
+		}
+
+		introduced := commits[j]
+		message := strings.TrimSpace(strings.SplitN(introduced.Commit.Message, "\n", 2)[0])
+
+		releases = append(releases, models.Release{
+			Version:     tagVersion,
+			Date:        introduced.Commit.Committer.Date,
+			Title:       fmt.Sprintf("%s %s", pkgName, tagVersion),
+			Description: message,
+			URL:         introduced.HTMLURL,
+			Type:        "tap-update",
+			Prerelease:  version.IsPrerelease(tagVersion, version.Semver),
+		})
+
+		i = j
+	}
+
+	return releases
+}
+
+// fetchFileCommits lists the most recent commits touching a file via the GitHub commits API
+func fetchFileCommits(owner, repo, path string, limit int) ([]GitHubCommit, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits?path=%s&per_page=%d", owner, repo, path, limit)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch commits: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 || resp.StatusCode == 429 {
+		return nil, fmt.Errorf("rate limited by GitHub API (%d)", resp.StatusCode)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var commits []GitHubCommit
+	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return commits, nil
+}
+
 // parseRubyFormula extracts metadata from .rb file using regex
 func parseRubyFormula(content string) FormulaMetadata {
 	metadata := FormulaMetadata{}
@@ -0,0 +1,58 @@
+package bluefin
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestCommit(sha, message string, date time.Time) GitHubCommit {
+	var commit GitHubCommit
+	commit.SHA = sha
+	commit.HTMLURL = "https://github.com/ublue-os/homebrew-tap/commit/" + sha
+	commit.Commit.Message = message
+	commit.Commit.Committer.Date = date
+	return commit
+}
+
+func TestBuildTapVersionHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	// Newest first, as returned by the GitHub commits API
+	commits := []GitHubCommit{
+		newTestCommit("c4", "chore: fix livecheck", day(4)),
+		newTestCommit("c3", "bluefin-cli 1.2.0\n\nBody", day(3)),
+		newTestCommit("c2", "bluefin-cli 1.1.0", day(2)),
+		newTestCommit("c1", "bluefin-cli: add formula", day(1)),
+	}
+	versions := []string{"1.2.0", "1.2.0", "1.1.0", "1.0.0"}
+
+	releases := buildTapVersionHistory(commits, versions, "bluefin-cli", true)
+	if len(releases) != 3 {
+		t.Fatalf("Expected 3 releases, got %d: %+v", len(releases), releases)
+	}
+
+	want := []struct {
+		version string
+		date    time.Time
+		message string
+	}{
+		{"1.2.0", day(3), "bluefin-cli 1.2.0"},
+		{"1.1.0", day(2), "bluefin-cli 1.1.0"},
+		{"1.0.0", day(1), "bluefin-cli: add formula"},
+	}
+	for i, w := range want {
+		r := releases[i]
+		if r.Version != w.version || !r.Date.Equal(w.date) || r.Description != w.message {
+			t.Errorf("Release %d = %+v, want version %s date %s message %q", i, r, w.version, w.date, w.message)
+		}
+		if r.Type != "tap-update" {
+			t.Errorf("Release %d has type %q, want tap-update", i, r.Type)
+		}
+	}
+
+	// With a truncated history the oldest version's introduction date is unknown
+	releases = buildTapVersionHistory(commits, versions, "bluefin-cli", false)
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases for incomplete history, got %d", len(releases))
+	}
+}
@@ -0,0 +1,28 @@
+package bluefin
+
+import "testing"
+
+func TestHomebrewAppIDs(t *testing.T) {
+	formula := convertHomebrewFormulaToApp(HomebrewFormula{Name: "docker"})
+	cask := convertHomebrewCaskToApp(HomebrewCask{Token: "docker"})
+	if formula.ID != "homebrew-docker" {
+		t.Errorf("formula ID = %q, want homebrew-docker", formula.ID)
+	}
+	if cask.ID != "homebrew-cask-docker" {
+		t.Errorf("cask ID = %q, want homebrew-cask-docker", cask.ID)
+	}
+
+	tests := []struct {
+		name string
+		cask bool
+		want string
+	}{
+		{"ublue-os/tap/bluefin-cli", false, "homebrew-ublue-os-tap-bluefin-cli"},
+		{"ublue-os/tap/jetbrains-toolbox", true, "homebrew-cask-ublue-os-tap-jetbrains-toolbox"},
+	}
+	for _, tt := range tests {
+		if got := createMinimalHomebrewApp(tt.name, tt.cask).ID; got != tt.want {
+			t.Errorf("createMinimalHomebrewApp(%q, %v).ID = %q, want %q", tt.name, tt.cask, got, tt.want)
+		}
+	}
+}
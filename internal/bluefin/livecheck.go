@@ -0,0 +1,122 @@
+package bluefin
+
+import (
+	"log"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// LivecheckThresholds controls when a Homebrew package is flagged as lagging upstream.
+// A package is outdated once it is VersionsBehind or more releases behind, or when the
+// first upstream release it is missing has been out for DaysBehind or more days.
+type LivecheckThresholds struct {
+	VersionsBehind int
+	DaysBehind     int
+}
+
+// DefaultLivecheckThresholds are used when the pipeline is run without overrides
+var DefaultLivecheckThresholds = LivecheckThresholds{VersionsBehind: 2, DaysBehind: 14}
+
+// CheckHomebrewUpstream compares each homebrew-core package's stable version against the
+// upstream releases and tags fetched by github.EnrichWithGitHubReleases and records the result
+// on App.UpstreamStatus. The pipeline runs it right after GitHub enrichment, before
+// merge.Releases combines releases across sources and before releases are sorted and normalized.
+func CheckHomebrewUpstream(apps []models.App, thresholds LivecheckThresholds) []models.App {
+	checked := 0
+	outdated := 0
+
+	for i := range apps {
+		app := &apps[i]
+		if app.PackageType != "homebrew" || app.HomebrewInfo == nil || app.HomebrewInfo.Tap != "homebrew/core" {
+			continue
+		}
+		if len(app.HomebrewInfo.Versions) == 0 || app.HomebrewInfo.Versions[0] == "" {
+			continue
+		}
+
+		status := compareUpstream(app.HomebrewInfo.Versions[0], app.Releases, thresholds, time.Now())
+		if status == nil {
+			continue
+		}
+
+		app.UpstreamStatus = status
+		checked++
+		if status.Outdated {
+			outdated++
+			log.Printf("  %s lags upstream: %s vs %s (%d versions, %d days behind)",
+				app.ID, status.PackagedVersion, status.UpstreamVersion, status.VersionsBehind, status.DaysBehind)
+		}
+	}
+
+	log.Printf("✅ Compared %d Homebrew packages against upstream (%d outdated)", checked, outdated)
+	return apps
+}
+
+// compareUpstream compares a packaged version against the upstream GitHub releases and tags.
+// The latest upstream version is the highest stable one, whatever the slice order; a version
+// reported both as a release and a tag counts once. Returns nil when there are no comparable
+// upstream releases.
+func compareUpstream(packaged string, releases []models.Release, thresholds LivecheckThresholds, now time.Time) *models.UpstreamStatus {
+	current, ok := version.Parse(packaged)
+	if !ok {
+		return nil
+	}
+
+	var latest *models.Release
+	var latestVersion version.Version
+	var oldestMissing *models.Release
+	versionsBehind := 0
+	seen := make(map[string]bool)
+
+	for i := range releases {
+		release := &releases[i]
+		if release.Type != "github-release" && release.Type != "github-tag" {
+			continue
+		}
+		// Homebrew stable never tracks pre-releases
+		if release.Prerelease || version.IsPrerelease(release.Version, version.Semver) {
+			continue
+		}
+
+		upstream, ok := version.Parse(release.Version)
+		if !ok {
+			continue
+		}
+
+		if latest == nil || upstream.Compare(latestVersion) > 0 {
+			latest, latestVersion = release, upstream
+		}
+
+		if upstream.Compare(current) > 0 {
+			if !seen[upstream.Normalized()] {
+				seen[upstream.Normalized()] = true
+				versionsBehind++
+			}
+			if oldestMissing == nil || release.Date.Before(oldestMissing.Date) {
+				oldestMissing = release
+			}
+		}
+	}
+
+	if latest == nil {
+		return nil
+	}
+
+	status := &models.UpstreamStatus{
+		PackagedVersion: packaged,
+		UpstreamVersion: latest.Version,
+		UpstreamDate:    latest.Date.Format(time.RFC3339),
+		VersionsBehind:  versionsBehind,
+	}
+
+	if oldestMissing != nil {
+		status.DaysBehind = int(now.Sub(oldestMissing.Date).Hours() / 24)
+	}
+
+	status.Outdated = versionsBehind > 0 &&
+		(versionsBehind >= thresholds.VersionsBehind || status.DaysBehind >= thresholds.DaysBehind)
+
+	return status
+}
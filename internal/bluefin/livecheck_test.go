@@ -0,0 +1,87 @@
+package bluefin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestCompareUpstream(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) time.Time { return now.AddDate(0, 0, -d) }
+
+	releases := []models.Release{
+		{Version: "v0.26.0-rc.1", Date: daysAgo(1), Type: "github-release"},
+		{Version: "v0.25.1", Date: daysAgo(3), Type: "github-release"},
+		{Version: "v0.25.0", Date: daysAgo(20), Type: "github-release"},
+		{Version: "v0.24.0", Date: daysAgo(90), Type: "github-release"},
+		{Version: "0.24.0", Date: daysAgo(80), Type: "appstream"},
+	}
+	thresholds := LivecheckThresholds{VersionsBehind: 3, DaysBehind: 14}
+
+	tests := []struct {
+		name           string
+		packaged       string
+		versionsBehind int
+		daysBehind     int
+		outdated       bool
+	}{
+		{"up to date", "0.25.1", 0, 0, false},
+		{"one behind, recent", "0.25.0", 1, 3, false},
+		{"two behind, old", "0.24.0", 2, 20, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := compareUpstream(tt.packaged, releases, thresholds, now)
+			if status == nil {
+				t.Fatal("Expected status, got nil")
+			}
+			if status.UpstreamVersion != "v0.25.1" {
+				t.Errorf("UpstreamVersion = %q, want v0.25.1 (prereleases skipped)", status.UpstreamVersion)
+			}
+			if status.VersionsBehind != tt.versionsBehind {
+				t.Errorf("VersionsBehind = %d, want %d", status.VersionsBehind, tt.versionsBehind)
+			}
+			if status.DaysBehind != tt.daysBehind {
+				t.Errorf("DaysBehind = %d, want %d", status.DaysBehind, tt.daysBehind)
+			}
+			if status.Outdated != tt.outdated {
+				t.Errorf("Outdated = %v, want %v", status.Outdated, tt.outdated)
+			}
+		})
+	}
+
+	if status := compareUpstream("1.0", []models.Release{{Version: "1.0", Type: "appstream"}}, thresholds, now); status != nil {
+		t.Errorf("Expected nil status without upstream releases, got %+v", status)
+	}
+}
+
+func TestCompareUpstreamPicksHighestVersion(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) time.Time { return now.AddDate(0, 0, -d) }
+
+	// A backport release published after the newest one, and a newer tag without a release
+	releases := []models.Release{
+		{Version: "v1.9.4", Date: daysAgo(2), Type: "github-release"},
+		{Version: "v2.1.0", Date: daysAgo(10), Type: "github-release"},
+		{Version: "v2.2.0", Date: daysAgo(5), Type: "github-tag"},
+		{Version: "v2.1.0", Date: daysAgo(10), Type: "github-tag"},
+		{Version: "v2.0.0", Date: daysAgo(30), Type: "github-release"},
+	}
+
+	status := compareUpstream("2.0.0", releases, DefaultLivecheckThresholds, now)
+	if status == nil {
+		t.Fatal("Expected status, got nil")
+	}
+	if status.UpstreamVersion != "v2.2.0" {
+		t.Errorf("UpstreamVersion = %q, want v2.2.0", status.UpstreamVersion)
+	}
+	if status.VersionsBehind != 2 {
+		t.Errorf("VersionsBehind = %d, want 2 (v2.1.0 counted once)", status.VersionsBehind)
+	}
+	if status.DaysBehind != 10 {
+		t.Errorf("DaysBehind = %d, want 10", status.DaysBehind)
+	}
+}
@@ -8,11 +8,13 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
 )
 
 const (
@@ -81,28 +83,22 @@ func FetchBluefinReleases() ([]models.Release, error) {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	// Convert GitHub releases to our Release model
+	releases := toReleases(githubReleases)
+	log.Printf("✅ Fetched %d Bluefin OS releases", len(releases))
+	return releases, nil
+}
+
+// toReleases converts GitHub releases to our Release model, skipping drafts.
+// Pre-releases are kept and flagged (the pipeline's prerelease policy decides).
+func toReleases(githubReleases []GitHubRelease) []models.Release {
 	var releases []models.Release
 	for _, ghRelease := range githubReleases {
-		// Skip draft and pre-releases
-		if ghRelease.Draft || ghRelease.Prerelease {
+		if ghRelease.Draft {
 			continue
 		}
-
-		release := models.Release{
-			Version:     ghRelease.TagName,
-			Date:        ghRelease.PublishedAt,
-			Title:       ghRelease.Name,
-			Description: parseReleaseNotes(ghRelease.Body),
-			URL:         ghRelease.HTMLURL,
-			Type:        "bluefin-os-release",
-		}
-
-		releases = append(releases, release)
+		releases = append(releases, toRelease(ghRelease))
 	}
-
-	log.Printf("✅ Fetched %d Bluefin OS releases", len(releases))
-	return releases, nil
+	return releases
 }
 
 // parseReleaseNotes formats release notes for display
@@ -156,31 +152,27 @@ func FetchBluefinOSApps() ([]models.App, error) {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	// Track latest release for each stream
-	latestByStream := make(map[string]*GitHubRelease)
-
-	// Convert GitHub releases to App objects, keeping only latest per stream
+	// Group releases by stream; pre-releases are kept and flagged (the pipeline's prerelease policy decides)
+	byStream := make(map[string][]*GitHubRelease)
+	var streams []string
 	for i := range githubReleases {
 		ghRelease := &githubReleases[i]
-
-		// Skip draft and pre-releases
-		if ghRelease.Draft || ghRelease.Prerelease {
+		if ghRelease.Draft {
 			continue
 		}
 
-		// Parse OS-specific information to get stream
-		osInfo := parseOSInfo(*ghRelease)
-		stream := osInfo.Stream
-
-		// Only keep the latest release for each stream
-		if existing, ok := latestByStream[stream]; !ok || ghRelease.PublishedAt.After(existing.PublishedAt) {
-			latestByStream[stream] = ghRelease
+		stream := parseOSInfo(*ghRelease).Stream
+		if _, ok := byStream[stream]; !ok {
+			streams = append(streams, stream)
 		}
+		byStream[stream] = append(byStream[stream], ghRelease)
 	}
 
 	// Convert the latest releases to App objects
 	var apps []models.App
-	for _, ghRelease := range latestByStream {
+	for _, stream := range streams {
+		ghRelease, releases := streamReleases(byStream[stream])
+
 		// Parse OS-specific information
 		osInfo := parseOSInfo(*ghRelease)
 
@@ -204,16 +196,7 @@ func FetchBluefinOSApps() ([]models.App, error) {
 			FetchedAt:   time.Now(),
 			PackageType: "os",
 			OSInfo:      osInfo,
-			Releases: []models.Release{
-				{
-					Version:     ghRelease.TagName,
-					Date:        ghRelease.PublishedAt,
-					Title:       ghRelease.Name,
-					Description: parseReleaseNotes(ghRelease.Body),
-					URL:         ghRelease.HTMLURL,
-					Type:        "bluefin-os-release",
-				},
-			},
+			Releases:    releases,
 		}
 
 		apps = append(apps, app)
@@ -267,20 +250,14 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	// Get the latest LTS release
-	var latestRelease *GitHubRelease
+	// Pre-releases are kept and flagged (the pipeline's prerelease policy decides)
+	var candidates []*GitHubRelease
 	for i := range githubReleases {
-		ghRelease := &githubReleases[i]
-
-		// Skip draft and pre-releases
-		if ghRelease.Draft || ghRelease.Prerelease {
-			continue
-		}
-
-		if latestRelease == nil || ghRelease.PublishedAt.After(latestRelease.PublishedAt) {
-			latestRelease = ghRelease
+		if !githubReleases[i].Draft {
+			candidates = append(candidates, &githubReleases[i])
 		}
 	}
+	latestRelease, releases := streamReleases(candidates)
 
 	var apps []models.App
 	if latestRelease != nil {
@@ -307,16 +284,7 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 			FetchedAt:   time.Now(),
 			PackageType: "os",
 			OSInfo:      osInfo,
-			Releases: []models.Release{
-				{
-					Version:     latestRelease.TagName,
-					Date:        latestRelease.PublishedAt,
-					Title:       latestRelease.Name,
-					Description: parseReleaseNotes(latestRelease.Body),
-					URL:         latestRelease.HTMLURL,
-					Type:        "bluefin-os-release",
-				},
-			},
+			Releases:    releases,
 		}
 
 		apps = append(apps, app)
@@ -326,6 +294,54 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 	return apps, nil
 }
 
+// streamReleases picks the release an OS stream is shown as: its latest stable release, or its
+// latest pre-release when it has no stable one. Returns it with the pre-releases published
+// after it, newest first, or nil when there are no releases.
+func streamReleases(ghReleases []*GitHubRelease) (*GitHubRelease, []models.Release) {
+	var latest, latestStable *GitHubRelease
+	for _, ghRelease := range ghReleases {
+		if latest == nil || ghRelease.PublishedAt.After(latest.PublishedAt) {
+			latest = ghRelease
+		}
+		if !isPrerelease(*ghRelease) && (latestStable == nil || ghRelease.PublishedAt.After(latestStable.PublishedAt)) {
+			latestStable = ghRelease
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	if latestStable == nil {
+		latestStable = latest
+	}
+
+	releases := []models.Release{toRelease(*latestStable)}
+	for _, ghRelease := range ghReleases {
+		if ghRelease != latestStable && isPrerelease(*ghRelease) && ghRelease.PublishedAt.After(latestStable.PublishedAt) {
+			releases = append(releases, toRelease(*ghRelease))
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].Date.After(releases[j].Date) })
+	return latestStable, releases
+}
+
+// isPrerelease reports whether a release is marked as a pre-release or has a pre-release tag
+func isPrerelease(ghRelease GitHubRelease) bool {
+	return ghRelease.Prerelease || version.IsPrerelease(ghRelease.TagName, version.Semver)
+}
+
+// toRelease converts a GitHub release of an OS image
+func toRelease(ghRelease GitHubRelease) models.Release {
+	return models.Release{
+		Version:     ghRelease.TagName,
+		Date:        ghRelease.PublishedAt,
+		Title:       ghRelease.Name,
+		Description: parseReleaseNotes(ghRelease.Body),
+		URL:         ghRelease.HTMLURL,
+		Type:        "bluefin-os-release",
+		Prerelease:  isPrerelease(ghRelease),
+	}
+}
+
 // parseOSInfo extracts OS-specific information from release data
 func parseOSInfo(release GitHubRelease) *models.OSInfo {
 	// Parse tag name (e.g., "stable-20260203" or "gts-20260203")
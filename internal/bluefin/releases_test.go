@@ -0,0 +1,70 @@
+package bluefin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamReleases(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	ghReleases := []*GitHubRelease{
+		{TagName: "stable-20260205", PublishedAt: day(5), Prerelease: true},
+		{TagName: "stable-20260203", PublishedAt: day(3)},
+		{TagName: "stable-20260204-rc.1", PublishedAt: day(4)},
+		{TagName: "stable-20260201", PublishedAt: day(1)},
+		{TagName: "stable-20260202-beta", PublishedAt: day(2), Prerelease: true},
+	}
+
+	latest, releases := streamReleases(ghReleases)
+	if latest == nil || latest.TagName != "stable-20260203" {
+		t.Fatalf("latest = %+v, want stable-20260203", latest)
+	}
+
+	want := []struct {
+		version    string
+		prerelease bool
+	}{
+		{"stable-20260205", true},
+		{"stable-20260204-rc.1", true},
+		{"stable-20260203", false},
+	}
+	if len(releases) != len(want) {
+		t.Fatalf("got %d releases, want %d: %+v", len(releases), len(want), releases)
+	}
+	for i, w := range want {
+		if releases[i].Version != w.version || releases[i].Prerelease != w.prerelease {
+			t.Errorf("releases[%d] = %s (prerelease %v), want %s (prerelease %v)",
+				i, releases[i].Version, releases[i].Prerelease, w.version, w.prerelease)
+		}
+	}
+
+	// A stream with only pre-releases is shown as its latest pre-release
+	latest, releases = streamReleases(ghReleases[:1])
+	if latest.TagName != "stable-20260205" || len(releases) != 1 || !releases[0].Prerelease {
+		t.Errorf("pre-release-only stream = %s, %+v", latest.TagName, releases)
+	}
+}
+
+func TestToReleases(t *testing.T) {
+	releases := toReleases([]GitHubRelease{
+		{TagName: "stable-20260203"},
+		{TagName: "stable-20260204", Prerelease: true},
+		{TagName: "stable-20260205-rc.1"},
+		{TagName: "stable-20260206", Draft: true},
+	})
+
+	want := map[string]bool{"stable-20260203": false, "stable-20260204": true, "stable-20260205-rc.1": true}
+	if len(releases) != len(want) {
+		t.Fatalf("got %d releases, want %d: %+v", len(releases), len(want), releases)
+	}
+	for _, release := range releases {
+		prerelease, ok := want[release.Version]
+		if !ok {
+			t.Errorf("unexpected release %s", release.Version)
+			continue
+		}
+		if release.Prerelease != prerelease {
+			t.Errorf("%s: Prerelease = %v, want %v", release.Version, release.Prerelease, prerelease)
+		}
+	}
+}
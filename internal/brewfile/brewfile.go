@@ -0,0 +1,423 @@
+package brewfile
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// Kind is the type of a Brewfile entry (the directive at the start of the line)
+type Kind string
+
+const (
+	KindTap     Kind = "tap"
+	KindBrew    Kind = "brew"
+	KindCask    Kind = "cask"
+	KindMas     Kind = "mas"
+	KindVSCode  Kind = "vscode"
+	KindFlatpak Kind = "flatpak"
+)
+
+// DefaultFlatpakRemote is the remote brew bundle uses when a flatpak entry has no remote: option
+const DefaultFlatpakRemote = "flathub"
+
+// Entry represents a single parsed Brewfile directive
+type Entry struct {
+	Kind      Kind              // "tap", "brew", "cask", "mas", "vscode", or "flatpak"
+	Name      string            // Package name without tap prefix (e.g., "bat", "org.gnome.Calculator")
+	Tap       string            // Tap for fully-qualified brew/cask names (e.g., "ublue-os/tap")
+	URL       string            // Clone URL for tap entries, or url: option for flatpak entries
+	Remote    string            // Flatpak remote (e.g., "flathub")
+	Args      []string          // Values of the args: option (e.g., ["HEAD"])
+	Options   map[string]string // All key: value options as raw Ruby source (strings unquoted)
+	Condition string            // Ruby guard applied to the entry (e.g., "OS.linux?"), empty if unconditional
+	Commented bool              // True when the entry is commented out (e.g., `# brew "foo"`)
+	Line      int               // 1-based line number in the Brewfile
+}
+
+// FullName returns the fully-qualified package name including its tap, if any
+// (e.g., "ublue-os/tap/bluefin-cli" or "bat")
+func (e Entry) FullName() string {
+	if e.Tap != "" && (e.Kind == KindBrew || e.Kind == KindCask) {
+		return e.Tap + "/" + e.Name
+	}
+	return e.Name
+}
+
+// Active reports whether the entry is installed by brew bundle (i.e., not commented out)
+func (e Entry) Active() bool {
+	return !e.Commented
+}
+
+var (
+	// directiveRe matches the directive keyword at the start of a (possibly commented) line
+	directiveRe = regexp.MustCompile(`^(tap|brew|cask|mas|vscode|flatpak)(?:\s+|\()`)
+
+	// blockStartRe matches a conditional block opener like `if OS.linux?`
+	blockStartRe = regexp.MustCompile(`^(if|unless)\s+(.+)$`)
+
+	// elsifRe matches the next branch of a conditional block: `elsif OS.linux?`
+	elsifRe = regexp.MustCompile(`^elsif\s+(.+)$`)
+
+	// doBlockRe matches Ruby iterator blocks like `%w[a b].each do |name|`
+	doBlockRe = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?$`)
+
+	// optionRe matches Ruby keyword options: `key: value` or `:key => value`
+	optionRe = regexp.MustCompile(`^(?:(\w+):\s*|:(\w+)\s*=>\s*)(.*)$`)
+)
+
+// block is an open Ruby block: an if/unless chain, or an iterator whose lines are skipped
+type block struct {
+	cond  string   // Guard of the current branch, empty for iterator blocks
+	taken []string // Guards of the branches so far, negated by elsif/else
+}
+
+// Parse parses Brewfile content into typed entries.
+// Commented-out directives are returned with Commented set so callers can decide
+// whether to include them; plain comments and unrecognized Ruby are skipped.
+// Directives continued over several lines (after a trailing comma or inside brackets)
+// are joined and reported at their first line.
+func Parse(content []byte) []Entry {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	var entries []Entry
+	var blocks []block
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line, commented := cleanLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		// Track conditional Ruby blocks (only for active lines; commented Ruby is ignored)
+		if !commented {
+			if match := blockStartRe.FindStringSubmatch(line); match != nil {
+				cond := strings.TrimSpace(strings.TrimSuffix(match[2], " then"))
+				if match[1] == "unless" {
+					cond = "!(" + cond + ")"
+				}
+				blocks = append(blocks, block{cond: cond, taken: []string{cond}})
+				continue
+			}
+			if doBlockRe.MatchString(line) {
+				// Not a condition, but its `end` must not close an enclosing if block
+				blocks = append(blocks, block{})
+				continue
+			}
+			if match := elsifRe.FindStringSubmatch(line); match != nil && len(blocks) > 0 && blocks[len(blocks)-1].cond != "" {
+				top := &blocks[len(blocks)-1]
+				cond := strings.TrimSpace(strings.TrimSuffix(match[1], " then"))
+				top.cond = strings.Join(append(negated(top.taken), cond), " && ")
+				top.taken = append(top.taken, cond)
+				continue
+			}
+			if line == "else" && len(blocks) > 0 && blocks[len(blocks)-1].cond != "" {
+				top := &blocks[len(blocks)-1]
+				top.cond = strings.Join(negated(top.taken), " && ")
+				continue
+			}
+			if line == "end" && len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+				continue
+			}
+		}
+
+		if !directiveRe.MatchString(line) {
+			continue
+		}
+
+		// Join continuation lines of a multi-line directive
+		for continues(line) && i+1 < len(lines) {
+			next, _ := cleanLine(lines[i+1])
+			i++
+			line = strings.TrimSpace(strings.TrimSuffix(line, "\\"))
+			if next != "" {
+				line += " " + next
+			}
+		}
+
+		entry, ok := parseEntry(line)
+		if !ok {
+			continue
+		}
+
+		entry.Commented = commented
+		entry.Line = lineNum
+		if !commented && len(blocks) > 0 {
+			var guards []string
+			for _, b := range blocks {
+				if b.cond != "" {
+					guards = append(guards, b.cond)
+				}
+			}
+			if entry.Condition != "" {
+				guards = append(guards, entry.Condition)
+			}
+			entry.Condition = strings.Join(guards, " && ")
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Filter returns the active (non-commented) entries installed on Linux matching any of the
+// given kinds. Entries guarded by a condition that doesn't hold on Linux (OS.mac?) or that
+// can't be evaluated are skipped.
+func Filter(entries []Entry, kinds ...Kind) []Entry {
+	var result []Entry
+	for _, entry := range entries {
+		if !entry.Active() || !entry.OnLinux() {
+			continue
+		}
+		for _, kind := range kinds {
+			if entry.Kind == kind {
+				result = append(result, entry)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// cleanLine trims a line and removes comment markers, reporting whether the line was commented out
+func cleanLine(raw string) (string, bool) {
+	line := strings.TrimSpace(raw)
+	commented := false
+	if strings.HasPrefix(line, "#") {
+		commented = true
+		line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+	}
+	return stripComment(line), commented
+}
+
+// continues reports whether a directive goes on over the next line: it ends with a comma or
+// backslash, or leaves a bracket, brace or paren open
+func continues(line string) bool {
+	if strings.HasSuffix(line, ",") || strings.HasSuffix(line, "\\") {
+		return true
+	}
+	depth := 0
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{' || r == '(':
+			depth++
+		case r == ']' || r == '}' || r == ')':
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+// negated returns the negation of each guard
+func negated(guards []string) []string {
+	result := make([]string, len(guards))
+	for i, guard := range guards {
+		result[i] = "!(" + guard + ")"
+	}
+	return result
+}
+
+// parseEntry parses a single directive line (comment markers already removed)
+func parseEntry(line string) (Entry, bool) {
+	match := directiveRe.FindStringSubmatch(line)
+	if match == nil {
+		return Entry{}, false
+	}
+
+	entry := Entry{Kind: Kind(match[1])}
+	rest := strings.TrimSpace(line[len(match[1]):])
+
+	// Split off trailing modifier: brew "foo" if OS.linux?
+	rest, entry.Condition = splitModifier(rest)
+
+	// Allow the parenthesized call form: brew("foo", args: ["HEAD"])
+	if strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")") {
+		rest = strings.TrimSpace(rest[1 : len(rest)-1])
+	}
+
+	var positional []string
+	for _, part := range splitTopLevel(rest, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if opt := optionRe.FindStringSubmatch(part); opt != nil {
+			key := opt[1]
+			if key == "" {
+				key = opt[2]
+			}
+			value := strings.TrimSpace(opt[3])
+			if entry.Options == nil {
+				entry.Options = make(map[string]string)
+			}
+			entry.Options[key] = unquote(value)
+			if key == "args" {
+				entry.Args = parseArray(value)
+			}
+			continue
+		}
+
+		positional = append(positional, part)
+	}
+
+	// The package name is always a string literal; anything else is prose
+	// (e.g., a comment like "# brew tools below") or dynamic Ruby we can't evaluate
+	if len(positional) == 0 || !isQuoted(positional[0]) || unquote(positional[0]) == "" {
+		return Entry{}, false
+	}
+	for i := range positional {
+		positional[i] = unquote(positional[i])
+	}
+
+	entry.Name = positional[0]
+
+	switch entry.Kind {
+	case KindTap:
+		if len(positional) > 1 {
+			entry.URL = positional[1]
+		}
+	case KindBrew, KindCask:
+		// Fully-qualified names: owner/tap/name
+		if parts := strings.Split(entry.Name, "/"); len(parts) == 3 {
+			entry.Tap = parts[0] + "/" + parts[1]
+			entry.Name = parts[2]
+		}
+	case KindFlatpak:
+		entry.Remote = entry.Options["remote"]
+		if entry.Remote == "" {
+			entry.Remote = DefaultFlatpakRemote
+		}
+		entry.URL = entry.Options["url"]
+	}
+
+	return entry, true
+}
+
+// stripComment removes a trailing `# comment` that is outside string literals
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// splitModifier splits a trailing Ruby `if`/`unless` modifier from the arguments
+func splitModifier(s string) (string, string) {
+	depth := 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{' || r == '(':
+			depth++
+		case r == ']' || r == '}' || r == ')':
+			depth--
+		case r == ' ' && depth == 0:
+			tail := s[i+1:]
+			if strings.HasPrefix(tail, "if ") {
+				return strings.TrimSpace(s[:i]), strings.TrimSpace(tail[3:])
+			}
+			if strings.HasPrefix(tail, "unless ") {
+				return strings.TrimSpace(s[:i]), "!(" + strings.TrimSpace(tail[7:]) + ")"
+			}
+		}
+	}
+	return s, ""
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside quotes, arrays, hashes and parens
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	depth := 0
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{' || r == '(':
+			depth++
+		case r == ']' || r == '}' || r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+// parseArray parses a Ruby array literal of strings: ["a", "b"] or %w[a b]
+func parseArray(value string) []string {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "%w[") && strings.HasSuffix(value, "]") {
+		return strings.Fields(value[3 : len(value)-1])
+	}
+
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		// A single value is treated as a one-element array
+		if v := unquote(value); v != "" {
+			return []string{v}
+		}
+		return nil
+	}
+
+	var items []string
+	for _, item := range splitTopLevel(value[1:len(value)-1], ',') {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// isQuoted reports whether s is a single string literal
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]
+}
+
+// unquote strips surrounding quotes from a Ruby string literal or leading colon from a symbol
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if isQuoted(s) {
+		return s[1 : len(s)-1]
+	}
+	return strings.TrimPrefix(s, ":")
+}
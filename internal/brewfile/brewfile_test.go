@@ -0,0 +1,182 @@
+package brewfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := []byte(`# CLI tools shipped with Bluefin
+tap "ublue-os/tap"
+tap "user/custom", "https://example.com/user/homebrew-custom.git"
+
+brew "bat"
+brew "ublue-os/tap/bluefin-cli" # from our tap
+brew "neovim", args: ["HEAD"]
+# brew "disabled-tool"
+cask "font-fira-code"
+mas "Xcode", id: 497799835
+vscode "golang.go"
+flatpak "org.gnome.Calculator"
+flatpak "org.example.App", remote: "fedora", url: "https://example.com/repo"
+brew "linux-only" if OS.linux?
+
+if OS.mac?
+  brew "mac-only"
+else
+  brew "not-mac"
+end
+`)
+
+	entries := Parse(content)
+
+	want := []Entry{
+		{Kind: KindTap, Name: "ublue-os/tap", Line: 2},
+		{Kind: KindTap, Name: "user/custom", URL: "https://example.com/user/homebrew-custom.git", Line: 3},
+		{Kind: KindBrew, Name: "bat", Line: 5},
+		{Kind: KindBrew, Name: "bluefin-cli", Tap: "ublue-os/tap", Line: 6},
+		{Kind: KindBrew, Name: "neovim", Args: []string{"HEAD"}, Options: map[string]string{"args": `["HEAD"]`}, Line: 7},
+		{Kind: KindBrew, Name: "disabled-tool", Commented: true, Line: 8},
+		{Kind: KindCask, Name: "font-fira-code", Line: 9},
+		{Kind: KindMas, Name: "Xcode", Options: map[string]string{"id": "497799835"}, Line: 10},
+		{Kind: KindVSCode, Name: "golang.go", Line: 11},
+		{Kind: KindFlatpak, Name: "org.gnome.Calculator", Remote: "flathub", Line: 12},
+		{Kind: KindFlatpak, Name: "org.example.App", Remote: "fedora", URL: "https://example.com/repo",
+			Options: map[string]string{"remote": "fedora", "url": "https://example.com/repo"}, Line: 13},
+		{Kind: KindBrew, Name: "linux-only", Condition: "OS.linux?", Line: 14},
+		{Kind: KindBrew, Name: "mac-only", Condition: "OS.mac?", Line: 17},
+		{Kind: KindBrew, Name: "not-mac", Condition: "!(OS.mac?)", Line: 19},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+
+	for i := range want {
+		if !reflect.DeepEqual(entries[i], want[i]) {
+			t.Errorf("Entry %d:\n got  %+v\n want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestParseIgnoresProseAndDynamicRuby(t *testing.T) {
+	content := []byte(`# brew tools are listed below
+# Install flatpak apps from flathub
+%w[a b].each do |name|
+  brew name
+end
+brew "after-block"
+`)
+
+	entries := Parse(content)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Name != "after-block" || entries[0].Condition != "" {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+}
+
+func TestParseElsifAndMultiLine(t *testing.T) {
+	content := []byte(`if OS.mac?
+  brew "mac-only"
+elsif OS.linux?
+  brew "linux-only"
+else
+  brew "other"
+end
+brew "neovim", args: [
+  "HEAD",
+]
+flatpak "org.example.App",
+  remote: "fedora",
+  url: "https://example.com/repo"
+brew "after"
+`)
+
+	entries := Parse(content)
+	want := []Entry{
+		{Kind: KindBrew, Name: "mac-only", Condition: "OS.mac?", Line: 2},
+		{Kind: KindBrew, Name: "linux-only", Condition: "!(OS.mac?) && OS.linux?", Line: 4},
+		{Kind: KindBrew, Name: "other", Condition: "!(OS.mac?) && !(OS.linux?)", Line: 6},
+		{Kind: KindBrew, Name: "neovim", Args: []string{"HEAD"}, Options: map[string]string{"args": `[ "HEAD", ]`}, Line: 8},
+		{Kind: KindFlatpak, Name: "org.example.App", Remote: "fedora", URL: "https://example.com/repo",
+			Options: map[string]string{"remote": "fedora", "url": "https://example.com/repo"}, Line: 11},
+		{Kind: KindBrew, Name: "after", Line: 14},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse() =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := Parse([]byte(`tap "ublue-os/tap"
+brew "bat"
+# brew "commented"
+cask "font-hack"
+flatpak "org.gnome.Calculator"
+brew "linux-only" if OS.linux?
+brew "mac-only" if OS.mac?
+brew "not-mac" unless OS.mac?
+brew "arm-only" if Hardware::CPU.arm?
+cask "font-mac" if OS.mac? || ENV["FONTS"]
+if OS.mac?
+  cask "mac-app"
+elsif OS.linux? && true
+  brew "linux-branch"
+else
+  brew "else-branch"
+end
+`))
+
+	got := Filter(entries, KindBrew, KindCask)
+	var names []string
+	for _, entry := range got {
+		names = append(names, entry.FullName())
+	}
+
+	want := []string{"bat", "font-hack", "linux-only", "not-mac", "linux-branch"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Filter() = %v, want %v", names, want)
+	}
+}
+
+func TestOnLinux(t *testing.T) {
+	tests := map[string]bool{
+		"":                                   true,
+		"OS.linux?":                          true,
+		"OS.mac?":                            false,
+		"!(OS.mac?)":                         true,
+		"not OS.mac?":                        true,
+		"OS.linux? && !OS.mac?":              true,
+		"OS.mac? || OS.linux?":               true,
+		"OS.linux? or ENV.fetch(\"X\", nil)": true,
+		"OS.linux? && ENV[\"X\"]":            false,
+		"!(ENV[\"X\"])":                      false,
+		"Hardware::CPU.intel?":               false,
+		"OS.linux? &&":                       false,
+	}
+	for cond, want := range tests {
+		if got := (Entry{Condition: cond}).OnLinux(); got != want {
+			t.Errorf("OnLinux(%q) = %v, want %v", cond, got, want)
+		}
+	}
+}
+
+func TestFullName(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{Entry{Kind: KindBrew, Name: "bat"}, "bat"},
+		{Entry{Kind: KindBrew, Name: "bluefin-cli", Tap: "ublue-os/tap"}, "ublue-os/tap/bluefin-cli"},
+		{Entry{Kind: KindCask, Name: "jetbrains-toolbox", Tap: "ublue-os/tap"}, "ublue-os/tap/jetbrains-toolbox"},
+		{Entry{Kind: KindTap, Name: "ublue-os/tap"}, "ublue-os/tap"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.FullName(); got != tt.want {
+			t.Errorf("FullName() = %q, want %q", got, tt.want)
+		}
+	}
+}
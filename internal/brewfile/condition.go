@@ -0,0 +1,150 @@
+package brewfile
+
+import "strings"
+
+// linuxGuards are the guard atoms with a known value on Bluefin (Linux); any other Ruby
+// (ENV lookups, CPU checks) is unknown
+var linuxGuards = map[string]bool{
+	"OS.linux?": true,
+	"OS.mac?":   false,
+	"true":      true,
+	"false":     false,
+}
+
+// OnLinux reports whether brew bundle installs the entry on Linux: it has no condition, or
+// its condition holds on Linux. Conditions that can't be evaluated are treated as not holding.
+func (e Entry) OnLinux() bool {
+	if e.Condition == "" {
+		return true
+	}
+	p := &condParser{tokens: tokenizeCondition(e.Condition)}
+	value, known := p.or()
+	return known && value && p.pos == len(p.tokens)
+}
+
+// condParser evaluates a Ruby guard with three-valued logic: each result is a value and
+// whether it is known
+type condParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *condParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// or parses `a || b` (also `a or b`)
+func (p *condParser) or() (bool, bool) {
+	value, known := p.and()
+	for p.peek() == "||" || p.peek() == "or" {
+		p.pos++
+		v, k := p.and()
+		switch {
+		case (known && value) || (k && v):
+			value, known = true, true
+		case known && k:
+			value = false
+		default:
+			known = false
+		}
+	}
+	return value, known
+}
+
+// and parses `a && b` (also `a and b`)
+func (p *condParser) and() (bool, bool) {
+	value, known := p.not()
+	for p.peek() == "&&" || p.peek() == "and" {
+		p.pos++
+		v, k := p.not()
+		switch {
+		case (known && !value) || (k && !v):
+			value, known = false, true
+		case known && k:
+			value = true
+		default:
+			known = false
+		}
+	}
+	return value, known
+}
+
+// not parses `!a`, `not a`, `(a)` and guard atoms
+func (p *condParser) not() (bool, bool) {
+	token := p.peek()
+	p.pos++
+	switch token {
+	case "!", "not":
+		value, known := p.not()
+		return !value, known
+	case "(":
+		value, known := p.or()
+		if p.peek() != ")" {
+			return false, false
+		}
+		p.pos++
+		return value, known
+	case "", ")", "&&", "||", "and", "or":
+		return false, false
+	}
+	value, known := linuxGuards[token]
+	return value, known
+}
+
+// tokenizeCondition splits a guard into operators, parens and atoms. Parens directly after an
+// atom (method call arguments) and quoted strings stay part of the atom.
+func tokenizeCondition(cond string) []string {
+	var tokens []string
+	atom := strings.Builder{}
+	depth := 0
+	var quote rune
+	flush := func() {
+		if atom.Len() > 0 {
+			tokens = append(tokens, atom.String())
+			atom.Reset()
+		}
+	}
+
+	runes := []rune(cond)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			atom.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			atom.WriteRune(r)
+		case depth > 0:
+			atom.WriteRune(r)
+			if r == '(' {
+				depth++
+			} else if r == ')' {
+				depth--
+			}
+		case r == '(' && atom.Len() > 0:
+			depth++
+			atom.WriteRune(r)
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t':
+			flush()
+		case (r == '&' || r == '|') && i+1 < len(runes) && runes[i+1] == r:
+			flush()
+			tokens = append(tokens, string(r)+string(r))
+			i++
+		case r == '!' && atom.Len() == 0 && (i+1 >= len(runes) || runes[i+1] != '='):
+			tokens = append(tokens, "!")
+		default:
+			atom.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
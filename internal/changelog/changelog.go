@@ -0,0 +1,221 @@
+// Package changelog fills in missing release notes from CHANGELOG, NEWS and CHANGES files
+// kept in an app's source repository.
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// Files are the changelog paths tried in order; the first file that parses into sections wins
+var Files = []string{"NEWS", "NEWS.md", "CHANGELOG.md", "CHANGELOG", "CHANGES.md", "CHANGES", "debian/changelog"}
+
+const (
+	// Concurrency is the number of repositories fetched in parallel
+	Concurrency = 10
+
+	// maxFileSize caps how much of a changelog is read (long-lived projects keep decades of NEWS)
+	maxFileSize = 1 << 20
+)
+
+// EnrichWithChangelogs fetches changelog files for apps that have releases without descriptions
+// and attaches the matching per-version section to those releases. Only GitHub and GitLab
+// source repos are supported.
+func EnrichWithChangelogs(apps []models.App) []models.App {
+	ctx := context.Background()
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sem      = make(chan struct{}, Concurrency)
+		filled   int
+		repoHits int
+	)
+
+	enrichedApps := make([]models.App, len(apps))
+	copy(enrichedApps, apps)
+
+	for i := range enrichedApps {
+		app := &enrichedApps[i]
+		if app.SourceRepo == nil || !needsNotes(app.Releases) {
+			continue
+		}
+
+		urls := rawFileURLs(app.SourceRepo)
+		if len(urls) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *models.App, urls []string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			sections, source := fetchSections(ctx, client, urls)
+			if len(sections) == 0 {
+				return
+			}
+
+			// Copy the releases so the caller's slice isn't modified
+			releases := append([]models.Release(nil), app.Releases...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			n := attachSections(releases, sections, app.SourceRepo)
+			app.Releases = releases
+			if n > 0 {
+				filled += n
+				repoHits++
+				log.Printf("  %s: filled %d release notes from %s", app.ID, n, source)
+			}
+		}(app, urls)
+	}
+
+	wg.Wait()
+	log.Printf("✅ Filled %d release notes from changelog files in %d repositories", filled, repoHits)
+	return enrichedApps
+}
+
+// needsNotes reports whether any release is missing a description
+func needsNotes(releases []models.Release) bool {
+	for _, release := range releases {
+		if strings.TrimSpace(release.Description) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// rawFileURLs returns raw-file URLs for each candidate changelog path in the repository
+func rawFileURLs(repo *models.SourceRepo) []string {
+	var base string
+	switch repo.Type {
+	case "github":
+		if repo.Owner == "" || repo.Repo == "" {
+			return nil
+		}
+		base = fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/HEAD/", repo.Owner, repo.Repo)
+	case "gitlab":
+		if repo.URL == "" {
+			return nil
+		}
+		base = strings.TrimSuffix(strings.TrimSuffix(repo.URL, "/"), ".git") + "/-/raw/HEAD/"
+	default:
+		return nil
+	}
+
+	urls := make([]string, 0, len(Files))
+	for _, file := range Files {
+		urls = append(urls, base+file)
+	}
+	return urls
+}
+
+// fetchSections tries each URL in order and returns the sections of the first file that
+// parses into at least one version, along with that file's URL
+func fetchSections(ctx context.Context, client *http.Client, urls []string) ([]Section, string) {
+	for _, fileURL := range urls {
+		content, err := fetchFile(ctx, client, fileURL)
+		if err != nil {
+			log.Printf("⚠️  Failed to fetch %s: %v", fileURL, err)
+			continue
+		}
+		if content == "" {
+			continue
+		}
+
+		if sections := Parse(content); len(sections) > 0 {
+			return sections, fileURL
+		}
+	}
+	return nil, ""
+}
+
+// fetchFile downloads a raw file. Returns an empty string (and no error) when it doesn't exist.
+func fetchFile(ctx context.Context, client *http.Client, fileURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	// GitLab serves its sign-in page instead of a 404 for some missing files
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	return string(body), nil
+}
+
+// attachSections sets the description of every release lacking one to the changelog section
+// with the same normalized version, linking references against the source repo.
+// Returns the number of releases filled.
+func attachSections(releases []models.Release, sections []Section, repo *models.SourceRepo) int {
+	byVersion := make(map[string]Section, len(sections))
+	for _, section := range sections {
+		if section.Body == "" {
+			continue
+		}
+		// Normalized keys drop the prefix and debian packaging revisions ("1.2.3-1" -> "1.2.3")
+		key := normalizeVersion(section.Version)
+		if _, exists := byVersion[key]; !exists {
+			byVersion[key] = section
+		}
+	}
+
+	filled := 0
+	for i := range releases {
+		release := &releases[i]
+		if strings.TrimSpace(release.Description) != "" {
+			continue
+		}
+
+		section, ok := byVersion[normalizeVersion(release.Version)]
+		if !ok {
+			continue
+		}
+
+		release.Description = markdown.ToHTMLForRepo(section.Body, repo)
+		filled++
+	}
+
+	return filled
+}
+
+// normalizeVersion reduces a tag or heading version to the key merge matches releases by
+// (e.g., "v1.2.3" -> "1.2.3", "gnome-firmware-46.0" -> "46")
+func normalizeVersion(s string) string {
+	v, ok := version.Parse(s)
+	if !ok {
+		return strings.ToLower(strings.TrimSpace(s))
+	}
+	return v.Normalized()
+}
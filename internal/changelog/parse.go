@@ -0,0 +1,230 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// Section is one version's entry in a changelog file
+type Section struct {
+	Version string     // Version as written in the heading (e.g., "47.0", "1.2.3")
+	Date    *time.Time // Release date if the heading or trailer carries one
+	Body    string     // Section text (Markdown or plain text), dedented
+}
+
+var (
+	// isoDateRe finds an ISO date in a heading (Keep a Changelog: "## [1.2.3] - 2024-01-31")
+	isoDateRe = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})\b`)
+
+	// markdownHeadingRe matches ATX headings: "## [1.2.3] - 2024-01-31"
+	markdownHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+	// underlineRe matches setext underlines and GNOME NEWS rulers ("=======", "-------")
+	underlineRe = regexp.MustCompile(`^\s*(?:={3,}|-{3,})\s*$`)
+
+	// plainVersionRe matches GNOME NEWS headings without underline ("Version 47.0", "Overview of changes in 3.2")
+	plainVersionRe = regexp.MustCompile(`(?i)^(?:version\s+|overview of changes in\s+)`)
+
+	// debianHeaderRe matches debian/changelog entry headers: "pkg (1.2.3-1) unstable; urgency=medium"
+	debianHeaderRe = regexp.MustCompile(`^(\S+) \(([^)]+)\) [^;]+;.*urgency=`)
+
+	// debianTrailerRe matches debian/changelog trailers: " -- Name <email>  Mon, 01 Jan 2024 00:00:00 +0000"
+	debianTrailerRe = regexp.MustCompile(`^ -- .*?>\s+(.+)$`)
+)
+
+// Parse parses a changelog file into per-version sections, in file order (usually newest first).
+// Supports Keep a Changelog / Markdown headings, GNOME NEWS "Version X" blocks with or without
+// underlines, and debian/changelog entries.
+func Parse(content string) []Section {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if debianHeaderRe.MatchString(line) {
+			return parseDebian(lines)
+		}
+		break
+	}
+
+	return parseHeadings(lines)
+}
+
+// heading describes a line that starts a new section
+type heading struct {
+	level   int // 1-6 for Markdown headings; 1 for setext and plain version headings
+	version string
+	date    *time.Time
+	skip    int // Extra lines consumed by the heading (setext underline)
+}
+
+// parseHeadings parses Markdown and GNOME NEWS style changelogs
+func parseHeadings(lines []string) []Section {
+	var sections []Section
+	var current *Section
+	var body []string
+	versionLevel := 0
+
+	flush := func() {
+		if current != nil {
+			current.Body = cleanBody(body)
+			sections = append(sections, *current)
+		}
+		current = nil
+		body = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		h, ok := detectHeading(lines, i)
+		if ok && (h.version != "" || (versionLevel > 0 && h.level <= versionLevel)) {
+			if h.version != "" && versionLevel == 0 {
+				versionLevel = h.level
+			}
+
+			// Sub-headings below the version level (e.g., "### Added") belong to the section
+			if versionLevel == 0 || h.level <= versionLevel {
+				flush()
+				if h.version != "" {
+					current = &Section{Version: h.version, Date: h.date}
+				}
+				i += h.skip
+				continue
+			}
+		}
+
+		if current != nil {
+			body = append(body, lines[i])
+		}
+	}
+	flush()
+
+	return sections
+}
+
+// detectHeading reports whether lines[i] starts a section heading
+func detectHeading(lines []string, i int) (heading, bool) {
+	line := strings.TrimRight(lines[i], " \t")
+	if strings.TrimSpace(line) == "" {
+		return heading{}, false
+	}
+
+	// ATX heading: "## [1.2.3] - 2024-01-31"
+	if match := markdownHeadingRe.FindStringSubmatch(line); match != nil {
+		h := heading{level: len(match[1])}
+		h.version, h.date = parseHeadingText(match[2])
+		return h, true
+	}
+
+	// Headings must start at column 0 and look like a title, not a list item
+	if line[0] == ' ' || line[0] == '\t' || line[0] == '-' || line[0] == '*' || line[0] == '=' || len(line) > 80 {
+		return heading{}, false
+	}
+
+	// Setext heading: "Version 47.0" followed by "====" or "----"
+	if i+1 < len(lines) && underlineRe.MatchString(lines[i+1]) {
+		version, date := parseHeadingText(line)
+		if version == "" {
+			return heading{}, false
+		}
+		return heading{level: 1, version: version, date: date, skip: 1}, true
+	}
+
+	// Plain GNOME NEWS heading: "Version 47.0"
+	if plainVersionRe.MatchString(line) {
+		version, date := parseHeadingText(line)
+		if version != "" {
+			return heading{level: 1, version: version, date: date}, true
+		}
+	}
+
+	return heading{}, false
+}
+
+// parseHeadingText extracts the version and optional ISO date from heading text
+func parseHeadingText(text string) (string, *time.Time) {
+	// Remove the date first so "2024-01-31" isn't mistaken for a version
+	var date *time.Time
+	if match := isoDateRe.FindStringSubmatch(text); match != nil {
+		if parsed, err := time.Parse("2006-01-02", match[1]); err == nil {
+			date = &parsed
+		}
+		text = strings.Replace(text, match[0], "", 1)
+	}
+
+	// Only dotted versions (at least major.minor) count as headings
+	v, ok := version.Parse(text)
+	if !ok || len(v.Numbers) < 2 {
+		return "", date
+	}
+	return v.Text(), date
+}
+
+// parseDebian parses debian/changelog entries
+func parseDebian(lines []string) []Section {
+	var sections []Section
+	var current *Section
+	var body []string
+
+	for _, line := range lines {
+		if match := debianHeaderRe.FindStringSubmatch(line); match != nil {
+			current = &Section{Version: match[2]}
+			body = nil
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if match := debianTrailerRe.FindStringSubmatch(line); match != nil {
+			if parsed, err := time.Parse(time.RFC1123Z, strings.TrimSpace(match[1])); err == nil {
+				current.Date = &parsed
+			}
+			current.Body = cleanBody(body)
+			sections = append(sections, *current)
+			current = nil
+			continue
+		}
+
+		body = append(body, line)
+	}
+
+	return sections
+}
+
+// cleanBody trims blank lines and rulers around a section and removes common indentation
+// (so indented NEWS bullets aren't rendered as Markdown code blocks)
+func cleanBody(lines []string) string {
+	for len(lines) > 0 && (strings.TrimSpace(lines[0]) == "" || underlineRe.MatchString(lines[0])) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && (strings.TrimSpace(lines[len(lines)-1]) == "" || underlineRe.MatchString(lines[len(lines)-1])) {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		result[i] = strings.TrimRight(line, " \t")
+	}
+
+	return strings.Join(result, "\n")
+}
@@ -0,0 +1,181 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestParseKeepAChangelog(t *testing.T) {
+	content := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+- Something in progress
+
+## [1.2.0] - 2024-03-01
+
+### Added
+- New export dialog
+
+### Fixed
+- Crash on startup
+
+## [1.1.0] - 2024-01-15
+
+- Initial packaging
+
+[1.2.0]: https://example.com/compare/v1.1.0...v1.2.0
+`
+
+	sections := Parse(content)
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 sections, got %d: %+v", len(sections), sections)
+	}
+
+	if sections[0].Version != "1.2.0" {
+		t.Errorf("Version = %q, want 1.2.0", sections[0].Version)
+	}
+	if sections[0].Date == nil || sections[0].Date.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("Date = %v, want 2024-03-01", sections[0].Date)
+	}
+	if !strings.Contains(sections[0].Body, "### Fixed") || !strings.Contains(sections[0].Body, "- Crash on startup") {
+		t.Errorf("Sub-headings should stay in the section body, got %q", sections[0].Body)
+	}
+	if strings.Contains(sections[0].Body, "Initial packaging") {
+		t.Errorf("Section body leaked into the next version: %q", sections[0].Body)
+	}
+	if sections[1].Version != "1.1.0" {
+		t.Errorf("Version = %q, want 1.1.0", sections[1].Version)
+	}
+}
+
+func TestParseGNOMENews(t *testing.T) {
+	content := `Version 47.1
+============
+
+- Fix thumbnail generation
+- Updated translations
+
+Version 47.0
+------------
+
+ * Port to GTK 4
+ * Improve accessibility
+
+===========
+Version 46.3
+===========
+
+Bugs fixed:
+ - #123 crash when closing window
+`
+
+	sections := Parse(content)
+	if len(sections) != 3 {
+		t.Fatalf("Expected 3 sections, got %d: %+v", len(sections), sections)
+	}
+
+	want := []string{"47.1", "47.0", "46.3"}
+	for i, version := range want {
+		if sections[i].Version != version {
+			t.Errorf("sections[%d].Version = %q, want %q", i, sections[i].Version, version)
+		}
+	}
+
+	if sections[1].Body != "* Port to GTK 4\n* Improve accessibility" {
+		t.Errorf("Expected dedented body without trailing ruler, got %q", sections[1].Body)
+	}
+	if !strings.HasPrefix(sections[2].Body, "Bugs fixed:") {
+		t.Errorf("Expected body to start after the ruler, got %q", sections[2].Body)
+	}
+}
+
+func TestParsePlainNews(t *testing.T) {
+	content := `Overview of changes in gnome-foo 3.2.1
+
+  - Fix build with newer meson
+
+Overview of changes in gnome-foo 3.2.0
+
+  - New preferences dialog
+`
+
+	sections := Parse(content)
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 sections, got %d: %+v", len(sections), sections)
+	}
+	if sections[0].Version != "3.2.1" || sections[0].Body != "- Fix build with newer meson" {
+		t.Errorf("Unexpected first section: %+v", sections[0])
+	}
+}
+
+func TestParseDebian(t *testing.T) {
+	content := `foo (2.0.1-1) unstable; urgency=medium
+
+  * New upstream release.
+  * Fix crash on exit.
+
+ -- Jane Doe <jane@example.com>  Mon, 05 Feb 2024 10:00:00 +0000
+
+foo (2.0.0-2) unstable; urgency=low
+
+  * Rebuild.
+
+ -- Jane Doe <jane@example.com>  Thu, 01 Feb 2024 09:30:00 +0100
+`
+
+	sections := Parse(content)
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 sections, got %d: %+v", len(sections), sections)
+	}
+	if sections[0].Version != "2.0.1-1" {
+		t.Errorf("Version = %q, want 2.0.1-1", sections[0].Version)
+	}
+	if sections[0].Body != "* New upstream release.\n* Fix crash on exit." {
+		t.Errorf("Unexpected body %q", sections[0].Body)
+	}
+	if sections[0].Date == nil || sections[0].Date.Format("2006-01-02") != "2024-02-05" {
+		t.Errorf("Date = %v, want 2024-02-05", sections[0].Date)
+	}
+}
+
+func TestAttachSections(t *testing.T) {
+	sections := []Section{
+		{Version: "47.1", Body: "- Fix thumbnails"},
+		{Version: "2.0.1-1", Body: "* New upstream release."},
+		{Version: "47.0", Body: "- Port to GTK 4"},
+		{Version: "3.2", Body: "- Faster startup"},
+	}
+	releases := []models.Release{
+		{Version: "v47.1"},
+		{Version: "gnome-foo-47.0", Description: "<p>Already has notes</p>"},
+		{Version: "2.0.1"},
+		{Version: "1.0"},
+		{Version: "v3.2.0"},
+	}
+
+	filled := attachSections(releases, sections, nil)
+	if filled != 3 {
+		t.Errorf("filled = %d, want 3", filled)
+	}
+	if !strings.Contains(releases[0].Description, "Fix thumbnails") {
+		t.Errorf("Expected notes for v47.1, got %q", releases[0].Description)
+	}
+	if releases[1].Description != "<p>Already has notes</p>" {
+		t.Errorf("Existing description was overwritten: %q", releases[1].Description)
+	}
+	if !strings.Contains(releases[2].Description, "New upstream release") {
+		t.Errorf("Expected debian revision to be ignored when matching, got %q", releases[2].Description)
+	}
+	if releases[3].Description != "" {
+		t.Errorf("Expected no notes for unmatched version, got %q", releases[3].Description)
+	}
+	if !strings.Contains(releases[4].Description, "Faster startup") {
+		t.Errorf("Expected v3.2.0 to match the 3.2 heading like merge does, got %q", releases[4].Description)
+	}
+}
@@ -0,0 +1,286 @@
+// Package classify tags releases by what their notes and version change say about them:
+// security fixes, breaking changes, new features, bugfix-only and translation-only releases,
+// with a severity score for filtering the firehose.
+package classify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// Release tags
+const (
+	TagSecurity        = "security"
+	TagBreaking        = "breaking"
+	TagFeature         = "feature"
+	TagBugfixOnly      = "bugfix-only"
+	TagTranslationOnly = "translation-only"
+)
+
+// Severity of each tag (0-10). Security fixes with advisories use the advisory impact instead.
+var tagSeverity = map[string]int{
+	TagSecurity:        7,
+	TagBreaking:        6,
+	TagFeature:         3,
+	TagBugfixOnly:      2,
+	TagTranslationOnly: 1,
+}
+
+// impactSeverity maps advisory impact ratings to severities
+var impactSeverity = map[string]int{
+	"critical": 10,
+	"high":     8,
+	"moderate": 6,
+	"medium":   6,
+	"low":      4,
+}
+
+// Kinds of note lines
+const (
+	kindOther       = "other"
+	kindIgnored     = "ignored" // Boilerplate and maintenance lines that say nothing about the release
+	kindFeature     = "feature"
+	kindBugfix      = "bugfix"
+	kindTranslation = "translation"
+)
+
+// headingKinds map section headings to the kind of the lines under them, checked in order.
+// Lines under neutral headings ("What's Changed", "Changed") are classified on their own.
+var headingKinds = []struct {
+	re   *regexp.Regexp
+	kind string
+}{
+	{regexp.MustCompile(`(?i)\b(new contributors|contributors|full changelog|assets|checksums?|downloads?|install(ation)?|known issues|unresolved)\b`), kindIgnored},
+	{regexp.MustCompile(`(?i)\b(translations?|locali[sz]ations?|l10n|i18n)\b`), kindTranslation},
+	{regexp.MustCompile(`(?i)\b(bug ?fix(es)?|fixe[sd]|fixes)\b`), kindBugfix},
+	{regexp.MustCompile(`(?i)\b(new|features?|added|enhancements?|improvements?|developer|web platform|enterprise)\b`), kindFeature},
+	{regexp.MustCompile(`(?i)\b(maintenance|chores?|internal|dependenc(y|ies)|documentation|docs|ci|build)\b`), kindIgnored},
+}
+
+// Headings that mark every line under them as a security fix or breaking change
+var (
+	securityHeadingRe = regexp.MustCompile(`(?i)\bsecurity\b`)
+	breakingHeadingRe = regexp.MustCompile(`(?i)(^\W*breaking\b|\bbreaking changes?\b|\bincompatib(le|ilit(y|ies))\b)`)
+)
+
+// Line rules. Feature and bugfix rules look at how a line starts ("Add ...", "fix: ...") since
+// words like "add" and "new" appear in all kinds of notes.
+var (
+	securityRe    = regexp.MustCompile(`(?i)\b(cve-\d{4}-\d+|ghsa(-[a-z0-9]{4}){3}|security (fix(es)?|issues?|advisor(y|ies)|patch(es)?|bugs?)|vulnerabilit(y|ies)|vulnerable|xss|csrf|remote code execution|privilege escalation|buffer overflow|use-after-free|sandbox escape)\b`)
+	breakingRe    = regexp.MustCompile(`(?i)(\bbreaking[- ]changes?\b|(?-i:\bBREAKING\b)|^(\[breaking\]|breaking\s*:)|\bbackwards?[- ]incompatible\b|\bincompatible change|\bno longer support(s|ed)?\b|\b(drop(s|ped)?|remove[sd]?) support\b|^\w+(\([^)]*\))?!:)`)
+	translationRe = regexp.MustCompile(`(?i)(\b(translations?|translated|locali[sz]ations?|l10n|i18n)\b|^updated? \w+( \w+)? translation)`)
+	ignoredRe     = regexp.MustCompile(`(?i)^(\**full changelog|.* made their first contribution|(chore|docs?|ci|build|test|tests|refactor|style)(\([^)]*\))?:|bump \S+ from|update dependenc|merge (pull request|branch))`)
+	featureRe     = regexp.MustCompile(`(?i)^(feat(ure)?(\([^)]*\))?:|(add(s|ed)?|new|introduc(e|es|ed)|implement(s|ed)?|support(s|ed)? for|allow(s|ed)?|enable(s|d)?)\b)`)
+	bugfixRe      = regexp.MustCompile(`(?i)(^(fix(e[sd])?|bug ?fix(es)?|resolve[sd]?|correct(s|ed)?|prevent(s|ed)?|avoid(s|ed)?|work ?around)\b|^fix(\([^)]*\))?:|\b(crash(es|ed)?|regressions?|memory leaks?|hangs?|freezes?)\b)`)
+)
+
+// Releases tags each release of a list sorted newest first. scheme is the version scheme
+// hint of the app ("semver" major bumps count as breaking changes).
+func Releases(releases []models.Release, scheme version.Scheme) {
+	for i := range releases {
+		previous := previousVersion(releases, i)
+		releases[i].Tags, releases[i].Severity = Classify(releases[i], previous, scheme)
+	}
+}
+
+// previousVersion returns the version of the release before releases[i]: the next older
+// stable release, or for pre-releases the next older release of any kind
+func previousVersion(releases []models.Release, i int) string {
+	for _, older := range releases[i+1:] {
+		if releases[i].Prerelease || !older.Prerelease {
+			return older.Version
+		}
+	}
+	return ""
+}
+
+// counts tallies the kinds of lines in release notes
+type counts struct {
+	security, breaking                   bool
+	features, fixes, translations, other int
+}
+
+// Classify returns the tags and severity of a release, from its notes, security flags and
+// the version change since previous ("" when unknown)
+func Classify(release models.Release, previous string, scheme version.Scheme) ([]string, int) {
+	c := countLines(release.Description)
+	c.security = c.security || release.Security || len(release.Advisories) > 0
+
+	bump := versionBump(release, previous, scheme)
+	if bump == bumpMajor {
+		c.breaking = true
+	}
+
+	var tags []string
+	if c.security {
+		tags = append(tags, TagSecurity)
+	}
+	if c.breaking {
+		tags = append(tags, TagBreaking)
+	}
+	switch {
+	case c.features > 0:
+		tags = append(tags, TagFeature)
+	case c.breaking:
+		// Breaking releases are never "only" fixes or translations
+	case c.translations > 0 && c.fixes == 0 && c.other == 0 && !c.security:
+		tags = append(tags, TagTranslationOnly)
+	case c.fixes > 0 && (c.other == 0 || bump == bumpPatch):
+		tags = append(tags, TagBugfixOnly)
+	}
+
+	return tags, severity(tags, release.Advisories)
+}
+
+// countLines classifies each heading, paragraph and list item of HTML release notes
+func countLines(description string) counts {
+	var c counts
+	heading := kindOther
+	securitySection, breakingSection := false, false
+
+	for _, block := range markdown.Blocks(description) {
+		text := strings.TrimSpace(block.Text)
+		if block.Kind == markdown.BlockHeading {
+			heading = headingKind(text)
+			securitySection = securityHeadingRe.MatchString(text)
+			breakingSection = breakingHeadingRe.MatchString(text)
+			continue
+		}
+		if heading == kindIgnored || block.Kind == markdown.BlockPre {
+			continue
+		}
+
+		// Nested items elaborate on their parent item
+		if block.Kind == markdown.BlockItem && block.Depth > 1 {
+			continue
+		}
+
+		// Ignored lines ("docs: update SECURITY.md", "chore: ...") never tag a release
+		kind := lineKind(text)
+		if kind == kindIgnored {
+			continue
+		}
+
+		if securitySection || securityRe.MatchString(text) {
+			c.security = true
+		}
+		if breakingSection || breakingRe.MatchString(text) {
+			c.breaking = true
+		}
+
+		if kind == kindOther && heading != kindOther {
+			kind = heading
+		}
+		switch kind {
+		case kindFeature:
+			c.features++
+		case kindBugfix:
+			c.fixes++
+		case kindTranslation:
+			c.translations++
+		case kindOther:
+			if securitySection || securityRe.MatchString(text) {
+				c.fixes++ // Security fixes are fixes
+			} else if !breakingSection {
+				c.other++
+			}
+		}
+	}
+	return c
+}
+
+// headingKind returns the kind of lines under a heading
+func headingKind(heading string) string {
+	for _, h := range headingKinds {
+		if h.re.MatchString(heading) {
+			return h.kind
+		}
+	}
+	return kindOther
+}
+
+// lineKind classifies one line of release notes
+func lineKind(line string) string {
+	switch {
+	case ignoredRe.MatchString(line):
+		return kindIgnored
+	case translationRe.MatchString(line):
+		return kindTranslation
+	case featureRe.MatchString(line):
+		return kindFeature
+	case bugfixRe.MatchString(line):
+		return kindBugfix
+	}
+	return kindOther
+}
+
+// Version bumps between a release and the previous one
+const (
+	bumpUnknown = iota
+	bumpMajor
+	bumpMinor
+	bumpPatch
+)
+
+// versionBump compares a release's version with the previous version. Only semantic versions
+// are compared: calendar, GNOME and Mozilla majors move on a schedule, not on API breaks.
+// Before 1.0, a minor bump counts as major.
+func versionBump(release models.Release, previous string, scheme version.Scheme) int {
+	if previous == "" || scheme != version.Semver || release.Source() == "mozilla" {
+		return bumpUnknown
+	}
+
+	current, okCurrent := version.ParseScheme(release.Version, scheme)
+	prev, okPrev := version.ParseScheme(previous, scheme)
+	if !okCurrent || !okPrev || current.Scheme != version.Semver || prev.Scheme != version.Semver ||
+		len(current.Numbers) < 2 || len(prev.Numbers) < 2 || current.Compare(prev) <= 0 {
+		return bumpUnknown
+	}
+
+	switch {
+	case current.Numbers[0] != prev.Numbers[0]:
+		return bumpMajor
+	case current.Numbers[1] != prev.Numbers[1]:
+		if current.Numbers[0] == 0 {
+			return bumpMajor
+		}
+		return bumpMinor
+	}
+	return bumpPatch
+}
+
+// severity returns the highest severity of the tags, rating security fixes by their
+// advisories' impact when known
+func severity(tags []string, advisories []models.SecurityAdvisory) int {
+	score := 0
+	for _, tag := range tags {
+		s := tagSeverity[tag]
+		if impact := advisoryImpact(advisories); tag == TagSecurity && impact > 0 {
+			s = impact
+		}
+		if s > score {
+			score = s
+		}
+	}
+	return score
+}
+
+// advisoryImpact returns the severity of the most severe advisory or CVE, or 0 when unrated
+func advisoryImpact(advisories []models.SecurityAdvisory) int {
+	impact := 0
+	for _, advisory := range advisories {
+		if s := impactSeverity[advisory.Impact]; s > impact {
+			impact = s
+		}
+		for _, cve := range advisory.CVEs {
+			if s := impactSeverity[cve.Impact]; s > impact {
+				impact = s
+			}
+		}
+	}
+	return impact
+}
@@ -0,0 +1,186 @@
+package classify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name         string
+		release      models.Release
+		previous     string
+		scheme       version.Scheme
+		wantTags     []string
+		wantSeverity int
+	}{
+		{
+			name: "github release with features and fixes",
+			release: models.Release{Version: "v1.4.0", Type: "github-release", Description: markdown.ToHTML(
+				"## What's Changed\n\n* feat: add dark mode by @jane in #12\n* fix: crash on startup by @joe in #13\n\n## New Contributors\n\n* @jane made their first contribution in #12\n\n**Full Changelog**: v1.3.0...v1.4.0")},
+			previous:     "v1.3.0",
+			wantTags:     []string{TagFeature},
+			wantSeverity: 3,
+		},
+		{
+			name: "patch release with only fixes",
+			release: models.Release{Version: "1.3.2", Type: "github-release", Description: markdown.ToHTML(
+				"This release fixes two regressions.\n\n- Fixed thumbnails not loading\n- Resolved a hang when closing the window\n- chore: update CI")},
+			previous:     "1.3.1",
+			wantTags:     []string{TagBugfixOnly},
+			wantSeverity: 2,
+		},
+		{
+			name: "unversioned fixes under a Bug Fixes heading",
+			release: models.Release{Version: "24.08.2", Type: "appstream", Description: markdown.ToHTML(
+				"## Bug Fixes\n\n- Thumbnails load again\n- Better handling of large folders")},
+			wantTags:     []string{TagBugfixOnly},
+			wantSeverity: 2,
+		},
+		{
+			name: "translation-only GNOME release",
+			release: models.Release{Version: "47.1", Type: "gitlab-release", Description: markdown.ToHTML(
+				"- Updated Czech translation\n- Updated Brazilian Portuguese translation\n\n## Translations\n\n- Daniel (de)")},
+			previous:     "47.0",
+			scheme:       version.GNOME,
+			wantTags:     []string{TagTranslationOnly},
+			wantSeverity: 1,
+		},
+		{
+			name: "breaking change from notes",
+			release: models.Release{Version: "2.5.0", Type: "github-release", Description: markdown.ToHTML(
+				"## Breaking Changes\n\n- The `--legacy` flag was removed\n\n## Fixed\n\n- Fixed a crash")},
+			previous:     "2.4.3",
+			wantTags:     []string{TagBreaking},
+			wantSeverity: 6,
+		},
+		{
+			name: "line breaking fix is not a breaking change",
+			release: models.Release{Version: "1.6.1", Type: "github-release", Description: markdown.ToHTML(
+				"- Fix line breaking in labels\n- Fixed word breaking for CJK text")},
+			previous:     "1.6.0",
+			wantTags:     []string{TagBugfixOnly},
+			wantSeverity: 2,
+		},
+		{
+			name: "breaking prefix",
+			release: models.Release{Version: "1.7.0", Type: "github-release", Description: markdown.ToHTML(
+				"- BREAKING: config moved to ~/.config/app\n- Fixed a crash")},
+			previous:     "1.6.1",
+			wantTags:     []string{TagBreaking},
+			wantSeverity: 6,
+		},
+		{
+			name: "security wording in ignored and feature lines",
+			release: models.Release{Version: "1.8.0", Type: "github-release", Description: markdown.ToHTML(
+				"- docs: update SECURITY.md\n- Add security settings page")},
+			previous:     "1.7.2",
+			wantTags:     []string{TagFeature},
+			wantSeverity: 3,
+		},
+		{
+			name: "Keep a Changelog Removed section is not breaking",
+			release: models.Release{Version: "1.9.0", Type: "github-release", Description: markdown.ToHTML(
+				"### Added\n\n- Export to CSV\n\n### Removed\n\n- Unused preferences dialog")},
+			previous:     "1.8.0",
+			wantTags:     []string{TagFeature},
+			wantSeverity: 3,
+		},
+		{
+			name:         "semver major bump is breaking",
+			release:      models.Release{Version: "v3.0.0", Type: "github-release", Description: "<p>Fixed a memory leak</p>"},
+			previous:     "v2.9.1",
+			wantTags:     []string{TagBreaking},
+			wantSeverity: 6,
+		},
+		{
+			name:         "pre-1.0 minor bump is breaking",
+			release:      models.Release{Version: "0.9.0", Type: "github-release"},
+			previous:     "0.8.4",
+			wantTags:     []string{TagBreaking},
+			wantSeverity: 6,
+		},
+		{
+			name:         "GNOME major bump is not breaking",
+			release:      models.Release{Version: "48.0", Type: "gitlab-release", Description: "<p>Fixed a memory leak</p>"},
+			previous:     "47.3",
+			scheme:       version.GNOME,
+			wantTags:     []string{TagBugfixOnly},
+			wantSeverity: 2,
+		},
+		{
+			name:         "Firefox major bump is not breaking",
+			release:      models.Release{Version: "129.0", Type: "mozilla-release"},
+			previous:     "128.0.3",
+			wantTags:     nil,
+			wantSeverity: 0,
+		},
+		{
+			name: "security fix from notes",
+			release: models.Release{Version: "1.2.4", Type: "github-release", Description: markdown.ToHTML(
+				"- Fix CVE-2024-12345: heap overflow in the PNG loader")},
+			previous:     "1.2.3",
+			wantTags:     []string{TagSecurity, TagBugfixOnly},
+			wantSeverity: 7,
+		},
+		{
+			name: "security fix rated by advisory impact",
+			release: models.Release{
+				Version:  "128.0",
+				Type:     "mozilla-release",
+				Security: true,
+				Advisories: []models.SecurityAdvisory{{
+					ID:     "MFSA 2024-29",
+					Impact: "high",
+					CVEs:   []models.AdvisoryCVE{{ID: "CVE-2024-6604", Impact: "critical"}},
+				}},
+				Description: "<h2>New</h2><ul><li>Tab groups can be collapsed</li></ul>",
+			},
+			previous:     "127.0.2",
+			wantTags:     []string{TagSecurity, TagFeature},
+			wantSeverity: 10,
+		},
+		{
+			name:         "no notes",
+			release:      models.Release{Version: "1.2.1", Type: "appstream"},
+			previous:     "1.2.0",
+			wantTags:     nil,
+			wantSeverity: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, severity := Classify(tt.release, tt.previous, tt.scheme)
+			if !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", tags, tt.wantTags)
+			}
+			if severity != tt.wantSeverity {
+				t.Errorf("severity = %d, want %d", severity, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestReleasesComparesWithPreviousStable(t *testing.T) {
+	releases := []models.Release{
+		{Version: "2.0.0", Type: "github-release"},
+		{Version: "2.0.0-rc.1", Type: "github-release", Prerelease: true},
+		{Version: "1.9.0", Type: "github-release"},
+	}
+	Releases(releases, version.Semver)
+
+	if !reflect.DeepEqual(releases[0].Tags, []string{TagBreaking}) {
+		t.Errorf("2.0.0 tags = %v, want [breaking] (compared with 1.9.0, not the rc)", releases[0].Tags)
+	}
+	if !reflect.DeepEqual(releases[1].Tags, []string{TagBreaking}) {
+		t.Errorf("2.0.0-rc.1 tags = %v, want [breaking]", releases[1].Tags)
+	}
+	if releases[2].Tags != nil {
+		t.Errorf("1.9.0 tags = %v, want none (no previous release)", releases[2].Tags)
+	}
+}
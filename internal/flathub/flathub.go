@@ -7,6 +7,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
 )
 
 const (
@@ -25,11 +27,13 @@ var sourceOverridesJSON []byte
 
 // SourceOverride represents a manual override for source repository detection
 type SourceOverride struct {
-	Type  string `json:"type"`
-	URL   string `json:"url"`
-	Owner string `json:"owner"`
-	Repo  string `json:"repo"`
-	Notes string `json:"notes"`
+	Type       string `json:"type"` // Empty for feed-only overrides (repo is still auto-detected)
+	URL        string `json:"url"`
+	Owner      string `json:"owner"`
+	Repo       string `json:"repo"`
+	Feed       string `json:"feed,omitempty"`       // Atom/RSS feed announcing releases
+	FeedFilter string `json:"feedFilter,omitempty"` // Regular expression feed item titles must match
+	Notes      string `json:"notes"`
 }
 
 // SourceOverrides contains the full overrides mapping
@@ -209,6 +213,8 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 		PackageType:       "flatpak", // All apps from Flathub are Flatpaks
 	}
 
+	app.Feed = ExtractFeedSource(flathubApp.AppID)
+
 	if details != nil {
 		// Extract source repository (with override support)
 		sourceRepo := ExtractSourceRepo(flathubApp.AppID, details)
@@ -299,12 +305,24 @@ func FetchAppDetails(appID string) (*models.FlathubAppDetails, error) {
 	return &details, nil
 }
 
+// ExtractFeedSource returns the release feed declared for an app in source-overrides.json, if any
+func ExtractFeedSource(appID string) *models.FeedSource {
+	override, found := loadSourceOverrides().Overrides[appID]
+	if !found || override.Feed == "" {
+		return nil
+	}
+	return &models.FeedSource{
+		URL:    override.Feed,
+		Filter: override.FeedFilter,
+	}
+}
+
 // ExtractSourceRepo extracts source repository information from app details
 // Checks overrides first, then falls back to URL-based detection
 func ExtractSourceRepo(appID string, details *models.FlathubAppDetails) *models.SourceRepo {
 	// Check overrides first
 	overrides := loadSourceOverrides()
-	if override, found := overrides.Overrides[appID]; found {
+	if override, found := overrides.Overrides[appID]; found && override.Type != "" {
 		log.Printf("Using source override for %s: %s", appID, override.URL)
 		return &models.SourceRepo{
 			Type:  override.Type,
@@ -327,8 +345,8 @@ func ExtractSourceRepo(appID string, details *models.FlathubAppDetails) *models.
 		repoURL = bugtracker
 	} else {
 		// Take first available URL
-		for _, url := range details.URLs {
-			repoURL = url
+		for _, candidate := range details.URLs {
+			repoURL = candidate
 			break
 		}
 	}
@@ -342,11 +360,16 @@ func ExtractSourceRepo(appID string, details *models.FlathubAppDetails) *models.
 		return extractGitHubRepo(repoURL)
 	}
 
-	// Check if it's a GitLab URL (gitlab.com or self-hosted like gitlab.gnome.org)
-	if strings.Contains(repoURL, "gitlab") {
+	// Check if it's a GitLab URL (gitlab.com or self-hosted like gitlab.gnome.org, invent.kde.org)
+	if isGitLabURL(repoURL) {
 		return extractGitLabRepo(repoURL)
 	}
 
+	// Check if it's a Gitea/Forgejo URL (codeberg.org, gitea.com or a self-hosted instance)
+	if isGiteaURL(repoURL) {
+		return extractGiteaRepo(repoURL)
+	}
+
 	// Other repository
 	return &models.SourceRepo{
 		Type: "other",
@@ -378,26 +401,109 @@ func extractGitHubRepo(url string) *models.SourceRepo {
 	}
 }
 
-// extractGitLabRepo extracts owner/repo from a GitLab URL (supports gitlab.com and self-hosted)
-func extractGitLabRepo(url string) *models.SourceRepo {
-	// Match gitlab.*/owner/repo patterns (supports gitlab.com, gitlab.gnome.org, etc.)
-	re := regexp.MustCompile(`gitlab\.[^/]+/([^/]+)/([^/\s?#]+)`)
-	matches := re.FindStringSubmatch(url)
+// gitLabHosts are GitLab instances whose hostname doesn't contain a "gitlab" label
+var gitLabHosts = []string{"invent.kde.org", "salsa.debian.org", "framagit.org"}
 
-	if len(matches) < 3 {
+// isGitLabURL reports whether a URL's host is gitlab.com, a gitlab.* instance (gitlab.gnome.org)
+// or a known GitLab host. Only the host is checked, so paths mentioning gitlab don't count.
+func isGitLabURL(repoURL string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Host)
+	for _, known := range gitLabHosts {
+		if host == known {
+			return true
+		}
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "gitlab" {
+			return true
+		}
+	}
+	return false
+}
+
+// extractGitLabRepo extracts the namespace and project from a GitLab URL (supports gitlab.com,
+// self-hosted instances and nested groups like gitlab.gnome.org/World/Rust/fractal).
+// The URL is normalized to the project page, dropping "/-/issues" style suffixes.
+func extractGitLabRepo(repoURL string) *models.SourceRepo {
+	parsed, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil || parsed.Host == "" {
 		return &models.SourceRepo{
 			Type: "gitlab",
-			URL:  url,
+			URL:  repoURL,
 		}
 	}
 
-	owner := matches[1]
-	repo := strings.TrimSuffix(matches[2], ".git")
-	repo = strings.TrimSuffix(repo, "/") // Remove trailing slash if present
+	projectPath := parsed.Path
+	if idx := strings.Index(projectPath, "/-/"); idx >= 0 {
+		projectPath = projectPath[:idx]
+	}
+	projectPath = strings.TrimSuffix(strings.Trim(projectPath, "/"), ".git")
+
+	segments := strings.Split(projectPath, "/")
+	if len(segments) < 2 {
+		return &models.SourceRepo{
+			Type: "gitlab",
+			URL:  repoURL,
+		}
+	}
 
 	return &models.SourceRepo{
 		Type:  "gitlab",
-		URL:   url,
+		URL:   fmt.Sprintf("https://%s/%s", parsed.Host, projectPath),
+		Owner: strings.Join(segments[:len(segments)-1], "/"),
+		Repo:  segments[len(segments)-1],
+	}
+}
+
+// giteaHosts are public Gitea and Forgejo instances
+var giteaHosts = []string{"codeberg.org", "gitea.com", "git.disroot.org", "next.forgejo.org"}
+
+// isGiteaURL reports whether a URL points at a known Gitea/Forgejo host or a gitea.*/forgejo.* instance
+func isGiteaURL(repoURL string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Host)
+	for _, known := range giteaHosts {
+		if host == known {
+			return true
+		}
+	}
+	return strings.HasPrefix(host, "gitea.") || strings.HasPrefix(host, "forgejo.")
+}
+
+// extractGiteaRepo extracts owner/repo from a Gitea/Forgejo URL
+// (e.g., https://codeberg.org/owner/repo/releases -> owner "owner", repo "repo")
+func extractGiteaRepo(repoURL string) *models.SourceRepo {
+	parsed, err := url.Parse(strings.TrimSpace(repoURL))
+	if err != nil {
+		return &models.SourceRepo{
+			Type: "gitea",
+			URL:  repoURL,
+		}
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return &models.SourceRepo{
+			Type: "gitea",
+			URL:  repoURL,
+		}
+	}
+
+	owner := segments[0]
+	repo := strings.TrimSuffix(segments[1], ".git")
+
+	return &models.SourceRepo{
+		Type:  "gitea",
+		URL:   fmt.Sprintf("https://%s/%s/%s", parsed.Host, owner, repo),
 		Owner: owner,
 		Repo:  repo,
 	}
@@ -439,6 +545,7 @@ func ConvertFlathubReleases(releases []models.FlathubReleaseEntry) []models.Rele
 			Title:       fmt.Sprintf("Version %s", release.Version),
 			Description: release.Description,
 			Type:        "appstream",
+			Prerelease:  release.Type == "development" || version.IsPrerelease(release.Version, version.Semver),
 		})
 	}
 
@@ -0,0 +1,26 @@
+package flathub
+
+import "testing"
+
+func TestSourceRepoHostDetection(t *testing.T) {
+	tests := []struct {
+		url    string
+		gitlab bool
+		gitea  bool
+	}{
+		{"https://gitlab.com/owner/repo", true, false},
+		{"https://gitlab.gnome.org/World/Rust/fractal", true, false},
+		{"https://invent.kde.org/utilities/kate", true, false},
+		{"https://codeberg.org/x/gitlab-ci-tool", false, true},
+		{"https://example.com/projects/gitlab-mirror", false, false},
+		{"https://gitea.example.org/owner/repo", false, true},
+	}
+	for _, tt := range tests {
+		if got := isGitLabURL(tt.url); got != tt.gitlab {
+			t.Errorf("isGitLabURL(%q) = %v, want %v", tt.url, got, tt.gitlab)
+		}
+		if got := isGiteaURL(tt.url); got != tt.gitea {
+			t.Errorf("isGiteaURL(%q) = %v, want %v", tt.url, got, tt.gitea)
+		}
+	}
+}
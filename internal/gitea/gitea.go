@@ -0,0 +1,292 @@
+// Package gitea fetches releases from Gitea and Forgejo instances (codeberg.org, gitea.com
+// and self-hosted servers) through the Gitea API v1.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/hosttoken"
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// ReleasesPerRepo is the number of releases (or version-like tags) kept per repository
+const ReleasesPerRepo = 5
+
+// HostTokenEnv maps Gitea/Forgejo hosts to the environment variable holding their API token
+var HostTokenEnv = map[string]string{
+	"codeberg.org": "CODEBERG_TOKEN",
+	"gitea.com":    "GITEA_TOKEN",
+}
+
+// HostTokensEnv holds tokens for any other instance as "host=token,host=token"
+const HostTokensEnv = "GITEA_HOST_TOKENS"
+
+// GiteaRelease represents a release from the Gitea API v1
+type GiteaRelease struct {
+	TagName     string       `json:"tag_name"`
+	Name        string       `json:"name"`
+	Body        string       `json:"body"`
+	HTMLURL     string       `json:"html_url"`
+	Draft       bool         `json:"draft"`
+	Prerelease  bool         `json:"prerelease"`
+	CreatedAt   time.Time    `json:"created_at"`
+	PublishedAt time.Time    `json:"published_at"`
+	Assets      []GiteaAsset `json:"assets"`
+}
+
+// GiteaAsset represents a file attached to a Gitea release
+type GiteaAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	DownloadCount      int    `json:"download_count"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// GiteaTag represents a tag from the Gitea API v1
+type GiteaTag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"` // Annotation message (empty for lightweight tags)
+	Commit  struct {
+		Created time.Time `json:"created"`
+	} `json:"commit"`
+}
+
+// EnrichWithGiteaReleases fetches releases for apps with Gitea/Forgejo repos
+// and adds them to the app's release list (prioritizing actual source changelogs)
+func EnrichWithGiteaReleases(apps []models.App) []models.App {
+	tokens := hosttoken.Load(HostTokenEnv, HostTokensEnv)
+	ctx := context.Background()
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	enrichedApps := make([]models.App, len(apps))
+	copy(enrichedApps, apps)
+
+	for i := range enrichedApps {
+		app := &enrichedApps[i]
+		if app.SourceRepo == nil || app.SourceRepo.Type != "gitea" || app.SourceRepo.URL == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *models.App) {
+			defer wg.Done()
+			repo := app.SourceRepo
+			token := tokens[hosttoken.Host(repo.URL)]
+
+			releases, err := fetchGiteaReleases(ctx, token, repo.URL, repo.Owner, repo.Repo)
+			if err != nil {
+				log.Printf("⚠️  Failed to fetch Gitea releases for %s: %v", repo.URL, err)
+				return
+			}
+
+			// Fall back to version-like tags when the project publishes no releases
+			if len(releases) == 0 {
+				tagReleases, err := fetchGiteaTags(ctx, token, repo.URL, repo.Owner, repo.Repo)
+				if err != nil {
+					log.Printf("⚠️  Failed to fetch Gitea tags for %s: %v", repo.URL, err)
+				} else {
+					releases = tagReleases
+				}
+			}
+
+			if len(releases) == 0 {
+				return
+			}
+
+			mu.Lock()
+			// Prepend Gitea releases (they are from actual source, so prioritize them)
+			app.Releases = append(releases, app.Releases...)
+			log.Printf("✅ Added %d Gitea releases for %s", len(releases), app.ID)
+			mu.Unlock()
+		}(app)
+	}
+
+	wg.Wait()
+	return enrichedApps
+}
+
+// repoAPIURL builds the Gitea API v1 repository URL (https://host/api/v1/repos/owner/repo)
+func repoAPIURL(repoURL, owner, repo string) (string, error) {
+	parsedURL, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("parse repo URL: %w", err)
+	}
+	if parsedURL.Host == "" {
+		return "", fmt.Errorf("missing host in URL: %s", repoURL)
+	}
+
+	if owner == "" || repo == "" {
+		segments := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+		if len(segments) < 2 {
+			return "", fmt.Errorf("invalid repository path in URL: %s", repoURL)
+		}
+		owner, repo = segments[0], strings.TrimSuffix(segments[1], ".git")
+	}
+
+	scheme := "https"
+	if parsedURL.Scheme == "http" {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/api/v1/repos/%s/%s", scheme, parsedURL.Host, url.PathEscape(owner), url.PathEscape(repo)), nil
+}
+
+// getJSON fetches apiURL and decodes the JSON response into v.
+// Returns false (and no error) when the repository doesn't exist.
+func getJSON(ctx context.Context, token, apiURL string, v interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, fmt.Errorf("API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("decode response: %w", err)
+	}
+	return true, nil
+}
+
+// fetchGiteaReleases fetches the latest published releases of a repository
+func fetchGiteaReleases(ctx context.Context, token, repoURL, owner, repo string) ([]models.Release, error) {
+	apiURL, err := repoAPIURL(repoURL, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var giteaReleases []GiteaRelease
+	if _, err := getJSON(ctx, token, fmt.Sprintf("%s/releases?draft=false&limit=%d", apiURL, ReleasesPerRepo), &giteaReleases); err != nil {
+		return nil, fmt.Errorf("fetch releases: %w", err)
+	}
+
+	return convertGiteaReleases(giteaReleases, repoURL), nil
+}
+
+// convertGiteaReleases converts Gitea releases to our Release model, skipping drafts
+func convertGiteaReleases(giteaReleases []GiteaRelease, repoURL string) []models.Release {
+	releases := []models.Release{}
+
+	for _, gr := range giteaReleases {
+		if gr.TagName == "" || gr.Draft {
+			continue
+		}
+
+		date := gr.PublishedAt
+		if date.IsZero() {
+			date = gr.CreatedAt
+		}
+		if date.IsZero() {
+			date = time.Now()
+			log.Printf("⚠️  Gitea release %s for %s has no published_at or created_at date, using current time", gr.TagName, repoURL)
+		}
+
+		title := gr.TagName
+		if gr.Name != "" {
+			title = gr.Name
+		}
+
+		releaseURL := gr.HTMLURL
+		if releaseURL == "" {
+			releaseURL = fmt.Sprintf("%s/releases/tag/%s", strings.TrimSuffix(repoURL, ".git"), url.PathEscape(gr.TagName))
+		}
+
+		var assets []models.ReleaseAsset
+		for _, asset := range gr.Assets {
+			assets = append(assets, models.ReleaseAsset{
+				Name:          asset.Name,
+				URL:           asset.BrowserDownloadURL,
+				Size:          asset.Size,
+				DownloadCount: asset.DownloadCount,
+			})
+		}
+		models.LinkAssetChecksums(assets)
+
+		releases = append(releases, models.Release{
+			Version:     gr.TagName,
+			Date:        date,
+			Title:       title,
+			Description: markdown.ToHTML(gr.Body),
+			URL:         releaseURL,
+			Type:        "gitea-release",
+			Assets:      assets,
+			Prerelease:  gr.Prerelease || version.IsPrerelease(gr.TagName, version.Semver),
+		})
+	}
+
+	return releases
+}
+
+// fetchGiteaTags lists version-like tags and converts them to releases of type "gitea-tag".
+// Used when a repository publishes no releases.
+func fetchGiteaTags(ctx context.Context, token, repoURL, owner, repo string) ([]models.Release, error) {
+	apiURL, err := repoAPIURL(repoURL, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []GiteaTag
+	if _, err := getJSON(ctx, token, apiURL+"/tags?limit=20", &tags); err != nil {
+		return nil, fmt.Errorf("fetch tags: %w", err)
+	}
+
+	releases := []models.Release{}
+	for _, tag := range tags {
+		if tag.Name == "" || !version.TagPattern.MatchString(tag.Name) {
+			continue
+		}
+
+		date := tag.Commit.Created
+		if date.IsZero() {
+			date = time.Now()
+			log.Printf("⚠️  Gitea tag %s for %s has no date, using current time", tag.Name, repoURL)
+		}
+
+		releases = append(releases, models.Release{
+			Version:     tag.Name,
+			Date:        date,
+			Title:       tag.Name,
+			Description: markdown.ToHTML(strings.TrimSpace(tag.Message)),
+			URL:         fmt.Sprintf("%s/releases/tag/%s", strings.TrimSuffix(repoURL, ".git"), url.PathEscape(tag.Name)),
+			Type:        "gitea-tag",
+			Prerelease:  version.IsPrerelease(tag.Name, version.Semver),
+		})
+
+		if len(releases) >= ReleasesPerRepo {
+			break
+		}
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,127 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// newGiteaServer returns an httptest stand-in for a Gitea instance hosting owner/repo
+func newGiteaServer(t *testing.T, releasesJSON, tagsJSON string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token secret" {
+			t.Errorf("Expected token header, got %q", got)
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/repo/releases":
+			fmt.Fprint(w, releasesJSON)
+		case "/api/v1/repos/owner/repo/tags":
+			fmt.Fprint(w, tagsJSON)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestEnrichWithGiteaReleases(t *testing.T) {
+	server := newGiteaServer(t, `[
+  {"tag_name": "v2.1.0-rc1", "name": "", "body": "Testing", "html_url": "", "draft": false, "prerelease": true, "published_at": "2026-02-01T00:00:00Z"},
+  {"tag_name": "v2.0.0", "name": "Version 2", "body": "**New** UI", "html_url": "https://codeberg.org/owner/repo/releases/tag/v2.0.0", "draft": false, "prerelease": false, "published_at": "2026-01-01T00:00:00Z",
+   "assets": [
+     {"name": "repo-2.0.0.tar.gz", "size": 4096, "download_count": 12, "browser_download_url": "https://codeberg.org/attachments/a"},
+     {"name": "repo-2.0.0.tar.gz.sha256", "size": 64, "download_count": 3, "browser_download_url": "https://codeberg.org/attachments/b"}
+   ]},
+  {"tag_name": "v2.0.1", "draft": true}
+]`, `[]`)
+	defer server.Close()
+
+	t.Setenv(HostTokensEnv, strings.TrimPrefix(server.URL, "http://")+"=secret")
+
+	apps := []models.App{
+		{
+			ID:         "org.example.Repo",
+			SourceRepo: &models.SourceRepo{Type: "gitea", URL: server.URL + "/owner/repo", Owner: "owner", Repo: "repo"},
+			Releases:   []models.Release{{Version: "2.0.0", Type: "appstream"}},
+		},
+		{
+			ID:         "org.example.GitHub",
+			SourceRepo: &models.SourceRepo{Type: "github", URL: "https://github.com/owner/repo"},
+		},
+	}
+
+	enriched := EnrichWithGiteaReleases(apps)
+	releases := enriched[0].Releases
+	if len(releases) != 3 {
+		t.Fatalf("Expected 2 Gitea releases plus the appstream release, got %d: %+v", len(releases), releases)
+	}
+	if len(apps[0].Releases) != 1 {
+		t.Errorf("Expected the input apps to be left unchanged, got %+v", apps[0].Releases)
+	}
+
+	if !releases[0].Prerelease || releases[0].Type != "gitea-release" {
+		t.Errorf("Expected flagged pre-release first, got %+v", releases[0])
+	}
+	if releases[0].URL != server.URL+"/owner/repo/releases/tag/v2.1.0-rc1" {
+		t.Errorf("Expected URL built from repo URL, got %s", releases[0].URL)
+	}
+
+	stable := releases[1]
+	if stable.Title != "Version 2" || stable.Prerelease {
+		t.Errorf("Unexpected stable release: %+v", stable)
+	}
+	if !strings.Contains(stable.Description, "<strong>New</strong>") {
+		t.Errorf("Description not rendered as HTML: %q", stable.Description)
+	}
+	if len(stable.Assets) != 2 || stable.Assets[0].DownloadCount != 12 || stable.Assets[0].ChecksumURL != "https://codeberg.org/attachments/b" {
+		t.Errorf("Unexpected assets: %+v", stable.Assets)
+	}
+
+	if releases[2].Type != "appstream" {
+		t.Errorf("Existing releases should follow Gitea releases, got %+v", releases[2])
+	}
+	if len(enriched[1].Releases) != 0 {
+		t.Error("Non-Gitea apps should not be enriched")
+	}
+}
+
+func TestFetchGiteaTagsFallback(t *testing.T) {
+	server := newGiteaServer(t, `[]`, `[
+  {"name": "nightly", "commit": {"created": "2026-03-01T00:00:00Z"}},
+  {"name": "1.4.0", "message": "Release 1.4.0", "commit": {"created": "2026-02-01T00:00:00Z"}}
+]`)
+	defer server.Close()
+
+	t.Setenv(HostTokensEnv, strings.TrimPrefix(server.URL, "http://")+"=secret")
+
+	apps := []models.App{
+		{ID: "org.example.Repo", SourceRepo: &models.SourceRepo{Type: "gitea", URL: server.URL + "/owner/repo", Owner: "owner", Repo: "repo"}},
+	}
+
+	releases := EnrichWithGiteaReleases(apps)[0].Releases
+	if len(releases) != 1 {
+		t.Fatalf("Expected 1 tag release, got %d: %+v", len(releases), releases)
+	}
+	if releases[0].Version != "1.4.0" || releases[0].Type != "gitea-tag" {
+		t.Errorf("Unexpected tag release: %+v", releases[0])
+	}
+}
+
+func TestRepoAPIURL(t *testing.T) {
+	got, err := repoAPIURL("https://codeberg.org/owner/repo.git", "", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != "https://codeberg.org/api/v1/repos/owner/repo" {
+		t.Errorf("repoAPIURL = %q", got)
+	}
+
+	if _, err := repoAPIURL("https://codeberg.org/owner", "", ""); err == nil {
+		t.Error("Expected error for URL without a repository")
+	}
+}
@@ -10,12 +10,23 @@ import (
 
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
 )
 
+const (
+	// ReleasesPerRepo is the number of latest releases fetched per repository
+	ReleasesPerRepo = 5
+
+	// RESTConcurrency limits concurrent REST requests when GraphQL is unavailable
+	RESTConcurrency = 10
+)
+
 // EnrichWithGitHubReleases fetches GitHub releases for apps with GitHub repos
-// and adds them to the app's release list (prioritizing actual source changelogs)
+// and adds them to the app's release list (prioritizing actual source changelogs).
+// Releases are fetched in batches via GraphQL, falling back to REST per batch
+// when GraphQL is unavailable.
 func EnrichWithGitHubReleases(apps []models.App) []models.App {
 	// Check if GitHub token is available
 	token := os.Getenv("GITHUB_TOKEN")
@@ -30,53 +41,117 @@ func EnrichWithGitHubReleases(apps []models.App) []models.App {
 	tc := oauth2.NewClient(ctx, ts)
 	client := github.NewClient(tc)
 
-	var (
-		wg sync.WaitGroup
-		mu sync.Mutex
-	)
-
 	enrichedApps := make([]models.App, len(apps))
 	copy(enrichedApps, apps)
 
-	// Process apps with GitHub repos in parallel
+	// Collect unique repositories (several apps may share one repo)
+	var repos []RepoRef
+	seen := make(map[RepoRef]bool)
 	for i := range enrichedApps {
 		app := &enrichedApps[i]
 		if app.SourceRepo == nil || app.SourceRepo.Type != "github" || app.SourceRepo.Owner == "" || app.SourceRepo.Repo == "" {
 			continue
 		}
+		ref := RepoRef{Owner: app.SourceRepo.Owner, Repo: app.SourceRepo.Repo}
+		if !seen[ref] {
+			seen[ref] = true
+			repos = append(repos, ref)
+		}
+	}
+
+	results := make(map[RepoRef][]models.Release, len(repos))
+	totalCost := 0
+	for start := 0; start < len(repos); start += GraphQLBatchSize {
+		end := start + GraphQLBatchSize
+		if end > len(repos) {
+			end = len(repos)
+		}
+		batch := repos[start:end]
+
+		batchResults, rateLimit, err := fetchReleasesGraphQL(ctx, tc, GraphQLEndpoint, batch, ReleasesPerRepo)
+		if err != nil {
+			log.Printf("⚠️  GraphQL batch failed (%v), falling back to REST for %d repos", err, len(batch))
+			batchResults = fetchReleasesREST(ctx, client, batch)
+		} else if rateLimit != nil {
+			totalCost += rateLimit.Cost
+			log.Printf("  GraphQL batch of %d repos: cost %d, %d/%d remaining (resets %s)",
+				len(batch), rateLimit.Cost, rateLimit.Remaining, rateLimit.Limit, rateLimit.ResetAt.Format(time.RFC3339))
+		}
+
+		for ref, releases := range batchResults {
+			results[ref] = releases
+		}
+	}
+	if totalCost > 0 {
+		log.Printf("GraphQL rate limit cost for %d repos: %d", len(repos), totalCost)
+	}
+
+	// Attach releases to apps
+	for i := range enrichedApps {
+		app := &enrichedApps[i]
+		if app.SourceRepo == nil || app.SourceRepo.Type != "github" {
+			continue
+		}
+		releases, ok := results[RepoRef{Owner: app.SourceRepo.Owner, Repo: app.SourceRepo.Repo}]
+		if !ok {
+			continue
+		}
 
+		// Prepend GitHub releases (they are from actual source, so prioritize them)
+		app.Releases = append(append([]models.Release{}, releases...), app.Releases...)
+		log.Printf("✅ Added %d GitHub releases for %s", len(releases), app.ID)
+	}
+
+	return enrichedApps
+}
+
+// fetchReleasesREST fetches releases for each repository via the REST API with bounded concurrency
+func fetchReleasesREST(ctx context.Context, client *github.Client, repos []RepoRef) map[RepoRef][]models.Release {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	results := make(map[RepoRef][]models.Release, len(repos))
+	semaphore := make(chan struct{}, RESTConcurrency)
+
+	for _, repo := range repos {
 		wg.Add(1)
-		go func(app *models.App) {
+		go func(ref RepoRef) {
 			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire semaphore
+			defer func() { <-semaphore }() // Release semaphore
 
-			releases, err := fetchGitHubReleases(ctx, client, app.SourceRepo.Owner, app.SourceRepo.Repo)
+			releases, err := fetchGitHubReleases(ctx, client, ref.Owner, ref.Repo)
 			if err != nil {
-				log.Printf("⚠️  Failed to fetch GitHub releases for %s/%s: %v",
-					app.SourceRepo.Owner, app.SourceRepo.Repo, err)
+				log.Printf("⚠️  Failed to fetch GitHub releases for %s: %v", ref, err)
 				return
 			}
 
+			// Fall back to version-like tags when the project publishes no releases
+			if len(releases) == 0 {
+				tagReleases, err := fetchGitHubTags(ctx, client, ref.Owner, ref.Repo)
+				if err != nil {
+					log.Printf("⚠️  Failed to fetch GitHub tags for %s: %v", ref, err)
+				} else {
+					releases = tagReleases
+				}
+			}
+
 			mu.Lock()
-			// Prepend GitHub releases (they are from actual source, so prioritize them)
-			app.Releases = append(releases, app.Releases...)
-			log.Printf("✅ Added %d GitHub releases for %s", len(releases), app.ID)
+			results[ref] = releases
 			mu.Unlock()
-
-			// Rate limiting: GitHub has a rate limit of 60 requests/hour for unauthenticated
-			// and 5000/hour for authenticated. This conservative sleep helps avoid hitting limits.
-			// In production with GITHUB_TOKEN, this is overly conservative but safe.
-			time.Sleep(500 * time.Millisecond)
-		}(app)
+		}(repo)
 	}
 
 	wg.Wait()
-	return enrichedApps
+	return results
 }
 
 // fetchGitHubReleases fetches the latest releases from a GitHub repository
 func fetchGitHubReleases(ctx context.Context, client *github.Client, owner, repo string) ([]models.Release, error) {
 	// Fetch up to 5 latest releases
-	opts := &github.ListOptions{PerPage: 5}
+	opts := &github.ListOptions{PerPage: ReleasesPerRepo}
 	githubReleases, _, err := client.Repositories.ListReleases(ctx, owner, repo, opts)
 	if err != nil {
 		return nil, fmt.Errorf("list releases: %w", err)
@@ -84,41 +159,57 @@ func fetchGitHubReleases(ctx context.Context, client *github.Client, owner, repo
 
 	var releases []models.Release
 	for _, gr := range githubReleases {
-		if gr.TagName == nil {
+		if gr.TagName == nil || gr.GetDraft() {
 			continue
 		}
 
-		date := time.Now()
+		var published *time.Time
 		if gr.PublishedAt != nil {
-			date = gr.PublishedAt.Time
-		} else {
-			log.Printf("⚠️  GitHub release %s for %s has no PublishedAt date, using current time", *gr.TagName, repo)
+			published = &gr.PublishedAt.Time
 		}
 
-		title := *gr.TagName
-		if gr.Name != nil && *gr.Name != "" {
-			title = *gr.Name
+		var assets []models.ReleaseAsset
+		for _, asset := range gr.Assets {
+			assets = append(assets, models.ReleaseAsset{
+				Name:          asset.GetName(),
+				URL:           asset.GetBrowserDownloadURL(),
+				Size:          int64(asset.GetSize()),
+				ContentType:   asset.GetContentType(),
+				DownloadCount: asset.GetDownloadCount(),
+			})
 		}
 
-		description := ""
-		if gr.Body != nil {
-			description = markdown.ToHTML(*gr.Body)
-		}
+		releases = append(releases, convertRelease(RepoRef{Owner: owner, Repo: repo}, *gr.TagName, gr.GetName(), gr.GetBody(), gr.GetHTMLURL(), published, assets, gr.GetPrerelease()))
+	}
 
-		url := ""
-		if gr.HTMLURL != nil {
-			url = *gr.HTMLURL
-		}
+	return releases, nil
+}
 
-		releases = append(releases, models.Release{
-			Version:     *gr.TagName,
-			Date:        date,
-			Title:       title,
-			Description: description,
-			URL:         url,
-			Type:        "github-release",
-		})
+// convertRelease converts GitHub release fields (from REST or GraphQL) to our Release model
+// Tags with a pre-release suffix are flagged even when the release itself isn't marked.
+func convertRelease(repo RepoRef, tagName, name, body, url string, publishedAt *time.Time, assets []models.ReleaseAsset, prerelease bool) models.Release {
+	date := time.Now()
+	if publishedAt != nil {
+		date = *publishedAt
+	} else {
+		log.Printf("⚠️  GitHub release %s for %s has no PublishedAt date, using current time", tagName, repo)
 	}
 
-	return releases, nil
+	title := tagName
+	if name != "" {
+		title = name
+	}
+
+	models.LinkAssetChecksums(assets)
+
+	return models.Release{
+		Version:     tagName,
+		Date:        date,
+		Title:       title,
+		Description: markdown.ToHTMLForRepo(body, repo.SourceRepo()),
+		URL:         url,
+		Type:        "github-release",
+		Assets:      assets,
+		Prerelease:  prerelease || version.IsPrerelease(tagName, version.Semver),
+	}
 }
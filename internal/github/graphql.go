@@ -0,0 +1,296 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+const (
+	// GraphQLEndpoint is the GitHub GraphQL API endpoint
+	GraphQLEndpoint = "https://api.github.com/graphql"
+
+	// GraphQLBatchSize is the number of repositories queried per GraphQL request (one alias each)
+	GraphQLBatchSize = 25
+
+	// AssetsPerRelease is the maximum number of assets fetched per release
+	AssetsPerRelease = 30
+)
+
+// RepoRef identifies a GitHub repository
+type RepoRef struct {
+	Owner string
+	Repo  string
+}
+
+// String returns the repository in owner/repo form
+func (r RepoRef) String() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// SourceRepo returns the repository as a source repo, for linking references in release notes
+func (r RepoRef) SourceRepo() *models.SourceRepo {
+	return &models.SourceRepo{Type: "github", URL: "https://github.com/" + r.String(), Owner: r.Owner, Repo: r.Repo}
+}
+
+// RateLimit contains GraphQL rate limit accounting from a response
+type RateLimit struct {
+	Cost      int       `json:"cost"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+// graphQLRelease represents a release node in a GraphQL response
+type graphQLRelease struct {
+	TagName      string     `json:"tagName"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	URL          string     `json:"url"`
+	PublishedAt  *time.Time `json:"publishedAt"`
+	IsPrerelease bool       `json:"isPrerelease"`
+	IsDraft      bool       `json:"isDraft"`
+	Assets       struct {
+		Nodes []struct {
+			Name          string `json:"name"`
+			Size          int64  `json:"size"`
+			ContentType   string `json:"contentType"`
+			DownloadCount int    `json:"downloadCount"`
+			DownloadURL   string `json:"downloadUrl"`
+		} `json:"nodes"`
+	} `json:"releaseAssets"`
+}
+
+// toAssets converts the release's asset nodes to our ReleaseAsset model
+func (r graphQLRelease) toAssets() []models.ReleaseAsset {
+	var assets []models.ReleaseAsset
+	for _, node := range r.Assets.Nodes {
+		assets = append(assets, models.ReleaseAsset{
+			Name:          node.Name,
+			URL:           node.DownloadURL,
+			Size:          node.Size,
+			ContentType:   node.ContentType,
+			DownloadCount: node.DownloadCount,
+		})
+	}
+	return assets
+}
+
+// graphQLRepository represents an aliased repository in a GraphQL response
+type graphQLRepository struct {
+	Releases struct {
+		Nodes []graphQLRelease `json:"nodes"`
+	} `json:"releases"`
+	Refs struct {
+		Nodes []graphQLTagRef `json:"nodes"`
+	} `json:"refs"`
+}
+
+// graphQLResponse is the envelope of a GraphQL response
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Type    string        `json:"type"`
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+// graphQLReleaseFields is the release selection shared by every repository alias
+const graphQLReleaseFields = `releases(first: $first, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes {
+        tagName name description url publishedAt isPrerelease isDraft
+        releaseAssets(first: $assets) { nodes { name size contentType downloadCount downloadUrl } }
+      }
+    }`
+
+// buildReleasesQuery builds a single query that fetches releases for every repo via aliases
+// (r0, r1, ...). Owners and names are passed as variables, never interpolated.
+func buildReleasesQuery(repos []RepoRef, perRepo int) (string, map[string]interface{}) {
+	variables := map[string]interface{}{"first": perRepo, "assets": AssetsPerRelease}
+	return buildRepositoriesQuery(repos, graphQLReleaseFields, "$first: Int!, $assets: Int!", variables)
+}
+
+// buildTagsQuery builds a query that fetches recent tags for every repo via aliases, for
+// repos that came back without releases
+func buildTagsQuery(repos []RepoRef) (string, map[string]interface{}) {
+	variables := map[string]interface{}{"tags": TagsScanned}
+	return buildRepositoriesQuery(repos, graphQLTagFields, "$tags: Int!", variables)
+}
+
+// buildRepositoriesQuery builds a query that applies one selection to every repo via aliases
+func buildRepositoriesQuery(repos []RepoRef, selection, params string, variables map[string]interface{}) (string, map[string]interface{}) {
+	var repoParams []string
+	var fields []string
+
+	for i, repo := range repos {
+		repoParams = append(repoParams, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		fields = append(fields, fmt.Sprintf("  r%d: repository(owner: $o%d, name: $n%d) {\n    %s\n  }", i, i, i, selection))
+		variables[fmt.Sprintf("o%d", i)] = repo.Owner
+		variables[fmt.Sprintf("n%d", i)] = repo.Repo
+	}
+
+	query := fmt.Sprintf("query(%s, %s) {\n  rateLimit { cost limit remaining resetAt }\n%s\n}",
+		strings.Join(repoParams, ", "), params, strings.Join(fields, "\n"))
+
+	return query, variables
+}
+
+// fetchReleasesGraphQL fetches releases for a batch of repositories in one GraphQL request.
+// Repos without releases fall back to version-like tags, fetched in a second request for
+// those repos only. Repositories that fail individually (e.g., not found) are omitted from
+// the result; an error is returned only when the releases request as a whole fails, so
+// callers can fall back to REST.
+func fetchReleasesGraphQL(ctx context.Context, httpClient *http.Client, endpoint string, repos []RepoRef, perRepo int) (map[RepoRef][]models.Release, *RateLimit, error) {
+	query, variables := buildReleasesQuery(repos, perRepo)
+	data, rateLimit, err := queryRepositories(ctx, httpClient, endpoint, query, variables, repos)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make(map[RepoRef][]models.Release, len(repos))
+	var untagged []RepoRef
+	for i, repo := range repos {
+		gqlRepo, ok := decodeRepository(data, i, repo)
+		if !ok {
+			continue
+		}
+
+		releases := []models.Release{}
+		for _, node := range gqlRepo.Releases.Nodes {
+			if node.TagName == "" || node.IsDraft {
+				continue
+			}
+			releases = append(releases, convertRelease(repo, node.TagName, node.Name, node.Description, node.URL, node.PublishedAt, node.toAssets(), node.IsPrerelease))
+		}
+		if len(releases) == 0 {
+			untagged = append(untagged, repo)
+		}
+
+		results[repo] = releases
+	}
+
+	// Fall back to version-like tags when the project publishes no releases
+	if len(untagged) > 0 {
+		query, variables := buildTagsQuery(untagged)
+		data, tagsRateLimit, err := queryRepositories(ctx, httpClient, endpoint, query, variables, untagged)
+		if err != nil {
+			log.Printf("⚠️  GraphQL tags request failed for %d repos without releases: %v", len(untagged), err)
+			return results, rateLimit, nil
+		}
+		rateLimit = addRateLimit(rateLimit, tagsRateLimit)
+
+		for i, repo := range untagged {
+			gqlRepo, ok := decodeRepository(data, i, repo)
+			if !ok || len(gqlRepo.Refs.Nodes) == 0 {
+				continue
+			}
+			tags := make([]tagInfo, 0, len(gqlRepo.Refs.Nodes))
+			for _, ref := range gqlRepo.Refs.Nodes {
+				tags = append(tags, ref.toTagInfo())
+			}
+			results[repo] = tagReleases(repo, tags, perRepo)
+		}
+	}
+
+	return results, rateLimit, nil
+}
+
+// queryRepositories sends an aliased repository query and returns its data by alias.
+// Per-alias errors are logged; an error is returned only when the request as a whole fails.
+func queryRepositories(ctx context.Context, httpClient *http.Client, endpoint, query string, variables map[string]interface{}, repos []RepoRef) (map[string]json.RawMessage, *RateLimit, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("graphql request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, nil, fmt.Errorf("graphql returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var gqlResp graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
+		return nil, nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if gqlResp.Data == nil {
+		if len(gqlResp.Errors) > 0 {
+			return nil, nil, fmt.Errorf("graphql error: %s", gqlResp.Errors[0].Message)
+		}
+		return nil, nil, fmt.Errorf("graphql response has no data")
+	}
+
+	// Per-alias errors (e.g., NOT_FOUND) leave that alias null but don't fail the batch
+	for _, gqlErr := range gqlResp.Errors {
+		if len(gqlErr.Path) == 0 {
+			continue
+		}
+		alias, _ := gqlErr.Path[0].(string)
+		var idx int
+		if _, err := fmt.Sscanf(alias, "r%d", &idx); err == nil && idx < len(repos) {
+			log.Printf("⚠️  GraphQL error for %s: %s", repos[idx], gqlErr.Message)
+		}
+	}
+
+	var rateLimit *RateLimit
+	if raw, ok := gqlResp.Data["rateLimit"]; ok && string(raw) != "null" {
+		rateLimit = &RateLimit{}
+		if err := json.Unmarshal(raw, rateLimit); err != nil {
+			rateLimit = nil
+		}
+	}
+
+	return gqlResp.Data, rateLimit, nil
+}
+
+// decodeRepository decodes the repository at alias r<i>, reporting false when it is missing
+func decodeRepository(data map[string]json.RawMessage, i int, repo RepoRef) (graphQLRepository, bool) {
+	var gqlRepo graphQLRepository
+	raw, ok := data[fmt.Sprintf("r%d", i)]
+	if !ok || string(raw) == "null" {
+		return gqlRepo, false
+	}
+	if err := json.Unmarshal(raw, &gqlRepo); err != nil {
+		log.Printf("⚠️  Failed to decode GraphQL response for %s: %v", repo, err)
+		return gqlRepo, false
+	}
+	return gqlRepo, true
+}
+
+// addRateLimit combines the rate limit accounting of two requests: costs add up and the
+// remaining budget is taken from the later one
+func addRateLimit(first, second *RateLimit) *RateLimit {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	combined := *second
+	combined.Cost += first.Cost
+	return &combined
+}
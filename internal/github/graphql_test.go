@@ -0,0 +1,186 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchReleasesGraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if !strings.Contains(body.Query, "r1: repository(owner: $o1, name: $n1)") {
+			t.Errorf("query missing alias for second repo:\n%s", body.Query)
+		}
+		if body.Variables["o0"] != "sharkdp" || body.Variables["n1"] != "missing" {
+			t.Errorf("unexpected variables: %v", body.Variables)
+		}
+
+		w.Write([]byte(`{
+  "data": {
+    "rateLimit": {"cost": 1, "limit": 5000, "remaining": 4999, "resetAt": "2026-01-01T00:00:00Z"},
+    "r0": {"releases": {"nodes": [
+      {"tagName": "v0.25.0", "name": "v0.25.0", "description": "**Bold**", "url": "https://github.com/sharkdp/bat/releases/tag/v0.25.0", "publishedAt": "2025-01-07T21:00:00Z", "isPrerelease": false, "isDraft": false,
+       "releaseAssets": {"nodes": [
+         {"name": "bat-v0.25.0-x86_64-unknown-linux-gnu.tar.gz", "size": 2048, "contentType": "application/gzip", "downloadCount": 1200, "downloadUrl": "https://github.com/sharkdp/bat/releases/download/v0.25.0/bat-v0.25.0-x86_64-unknown-linux-gnu.tar.gz"},
+         {"name": "SHA256SUMS", "size": 120, "contentType": "text/plain", "downloadCount": 40, "downloadUrl": "https://github.com/sharkdp/bat/releases/download/v0.25.0/SHA256SUMS"}
+       ]}},
+      {"tagName": "v0.24.0", "name": "", "description": "", "url": "https://github.com/sharkdp/bat/releases/tag/v0.24.0", "publishedAt": "2023-10-11T21:00:00Z", "isPrerelease": false, "isDraft": false}
+    ]}},
+    "r1": null
+  },
+  "errors": [{"type": "NOT_FOUND", "path": ["r1"], "message": "Could not resolve to a Repository"}]
+}`))
+	}))
+	defer server.Close()
+
+	repos := []RepoRef{{Owner: "sharkdp", Repo: "bat"}, {Owner: "nobody", Repo: "missing"}}
+	results, rateLimit, err := fetchReleasesGraphQL(context.Background(), server.Client(), server.URL, repos, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rateLimit == nil || rateLimit.Cost != 1 || rateLimit.Remaining != 4999 {
+		t.Errorf("Unexpected rate limit: %+v", rateLimit)
+	}
+
+	if _, ok := results[repos[1]]; ok {
+		t.Error("Missing repository should be omitted from results")
+	}
+
+	releases := results[repos[0]]
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(releases))
+	}
+	if releases[0].Version != "v0.25.0" || releases[0].Type != "github-release" {
+		t.Errorf("Unexpected release: %+v", releases[0])
+	}
+	if !strings.Contains(releases[0].Description, "<strong>Bold</strong>") {
+		t.Errorf("Description not rendered as HTML: %q", releases[0].Description)
+	}
+	if len(releases[0].Assets) != 2 {
+		t.Fatalf("Expected 2 assets, got %d", len(releases[0].Assets))
+	}
+	asset := releases[0].Assets[0]
+	if asset.DownloadCount != 1200 || asset.Size != 2048 || asset.ContentType != "application/gzip" {
+		t.Errorf("Unexpected asset: %+v", asset)
+	}
+	if asset.ChecksumURL != releases[0].Assets[1].URL {
+		t.Errorf("Expected SHA256SUMS to be linked as checksum, got %q", asset.ChecksumURL)
+	}
+	if releases[1].Title != "v0.24.0" {
+		t.Errorf("Expected title to fall back to tag, got %q", releases[1].Title)
+	}
+}
+
+func TestFetchReleasesGraphQLTagsForReposWithoutReleases(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		requests++
+
+		switch requests {
+		case 1:
+			if strings.Contains(body.Query, "refs(") {
+				t.Errorf("releases query should not request tags:\n%s", body.Query)
+			}
+			w.Write([]byte(`{"data": {
+  "rateLimit": {"cost": 1, "limit": 5000, "remaining": 4999, "resetAt": "2026-01-01T00:00:00Z"},
+  "r0": {"releases": {"nodes": [{"tagName": "v2.0.0", "url": "https://github.com/a/released/releases/tag/v2.0.0", "publishedAt": "2026-01-02T00:00:00Z"}]}},
+  "r1": {"releases": {"nodes": []}}
+}}`))
+		case 2:
+			if strings.Contains(body.Query, "r1:") || body.Variables["n0"] != "tagged" {
+				t.Errorf("tags query should only cover the repo without releases: %v\n%s", body.Variables, body.Query)
+			}
+			w.Write([]byte(`{"data": {
+  "rateLimit": {"cost": 1, "limit": 5000, "remaining": 4998, "resetAt": "2026-01-01T00:00:00Z"},
+  "r0": {"refs": {"nodes": [
+    {"name": "v1.1.0", "target": {"__typename": "Commit", "committedDate": "2026-01-03T00:00:00Z"}},
+    {"name": "nightly", "target": {"__typename": "Commit", "committedDate": "2026-01-04T00:00:00Z"}}
+  ]}}
+}}`))
+		default:
+			t.Errorf("unexpected request %d", requests)
+		}
+	}))
+	defer server.Close()
+
+	repos := []RepoRef{{Owner: "a", Repo: "released"}, {Owner: "a", Repo: "tagged"}}
+	results, rateLimit, err := fetchReleasesGraphQL(context.Background(), server.Client(), server.URL, repos, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if rateLimit == nil || rateLimit.Cost != 2 || rateLimit.Remaining != 4998 {
+		t.Errorf("Unexpected rate limit: %+v", rateLimit)
+	}
+
+	if releases := results[repos[0]]; len(releases) != 1 || releases[0].Type != "github-release" {
+		t.Errorf("Unexpected releases for %s: %+v", repos[0], releases)
+	}
+	if releases := results[repos[1]]; len(releases) != 1 || releases[0].Version != "v1.1.0" || releases[0].Type != "github-tag" {
+		t.Errorf("Unexpected releases for %s: %+v", repos[1], releases)
+	}
+}
+
+func TestFetchReleasesGraphQLUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, _, err := fetchReleasesGraphQL(context.Background(), server.Client(), server.URL, []RepoRef{{Owner: "a", Repo: "b"}}, 5)
+	if err == nil {
+		t.Fatal("Expected error so the caller falls back to REST")
+	}
+}
+
+func TestTagReleases(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return &d
+	}
+
+	tags := []tagInfo{
+		{Name: "nightly", Date: date("2026-02-01")},
+		{Name: "v1.1.0", Date: date("2026-01-15"), Message: "Release *1.1.0*"},
+		{Name: "v1.2.0", Date: date("2026-01-20")},
+		{Name: "foo-1.0.0", Date: date("2025-12-01")},
+	}
+
+	releases := tagReleases(RepoRef{Owner: "owner", Repo: "repo"}, tags, 2)
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(releases))
+	}
+	if releases[0].Version != "v1.2.0" || releases[1].Version != "v1.1.0" {
+		t.Errorf("Unexpected order: %s, %s", releases[0].Version, releases[1].Version)
+	}
+	if releases[0].Type != "github-tag" {
+		t.Errorf("Expected type github-tag, got %q", releases[0].Type)
+	}
+	if !strings.Contains(releases[1].Description, "<em>1.1.0</em>") {
+		t.Errorf("Annotation not rendered: %q", releases[1].Description)
+	}
+	if releases[0].URL != "https://github.com/owner/repo/releases/tag/v1.2.0" {
+		t.Errorf("Unexpected URL: %s", releases[0].URL)
+	}
+}
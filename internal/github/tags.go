@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+	"github.com/google/go-github/v57/github"
+)
+
+// TagsScanned is the number of most recent tags inspected per repository before filtering
+const TagsScanned = 20
+
+// tagInfo is a tag with its date and annotation, independent of the API it came from
+type tagInfo struct {
+	Name    string
+	Message string     // Annotation message (empty for lightweight tags)
+	Date    *time.Time // Tagger date for annotated tags, commit date otherwise
+}
+
+// graphQLTagRef represents a refs/tags/* node in a GraphQL response
+type graphQLTagRef struct {
+	Name   string `json:"name"`
+	Target struct {
+		TypeName      string     `json:"__typename"`
+		CommittedDate *time.Time `json:"committedDate"` // Lightweight tag pointing at a commit
+		Message       string     `json:"message"`       // Annotated tag
+		Tagger        *struct {
+			Date *time.Time `json:"date"`
+		} `json:"tagger"`
+		Target *struct {
+			CommittedDate *time.Time `json:"committedDate"`
+		} `json:"target"`
+	} `json:"target"`
+}
+
+// graphQLTagFields is the tag selection requested for repositories without releases
+const graphQLTagFields = `refs(refPrefix: "refs/tags/", first: $tags, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      nodes {
+        name
+        target {
+          __typename
+          ... on Commit { committedDate }
+          ... on Tag { message tagger { date } target { ... on Commit { committedDate } } }
+        }
+      }
+    }`
+
+// toTagInfo converts a GraphQL tag ref into a tagInfo
+func (ref graphQLTagRef) toTagInfo() tagInfo {
+	info := tagInfo{Name: ref.Name}
+	target := ref.Target
+
+	switch target.TypeName {
+	case "Tag":
+		info.Message = strings.TrimSpace(target.Message)
+		if target.Tagger != nil && target.Tagger.Date != nil {
+			info.Date = target.Tagger.Date
+		} else if target.Target != nil {
+			info.Date = target.Target.CommittedDate
+		}
+	default:
+		info.Date = target.CommittedDate
+	}
+
+	return info
+}
+
+// tagReleases converts version-like tags into releases of type "github-tag", newest first
+func tagReleases(repo RepoRef, tags []tagInfo, limit int) []models.Release {
+	var releases []models.Release
+
+	for _, tag := range tags {
+		if !version.TagPattern.MatchString(tag.Name) {
+			continue
+		}
+
+		date := time.Now()
+		if tag.Date != nil {
+			date = *tag.Date
+		} else {
+			log.Printf("⚠️  GitHub tag %s for %s has no date, using current time", tag.Name, repo)
+		}
+
+		releases = append(releases, models.Release{
+			Version:     tag.Name,
+			Date:        date,
+			Title:       tag.Name,
+			Description: markdown.ToHTMLForRepo(tag.Message, repo.SourceRepo()),
+			URL:         fmt.Sprintf("https://github.com/%s/releases/tag/%s", repo, tag.Name),
+			Type:        "github-tag",
+			Prerelease:  version.IsPrerelease(tag.Name, version.Semver),
+		})
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date.After(releases[j].Date)
+	})
+
+	if len(releases) > limit {
+		releases = releases[:limit]
+	}
+	return releases
+}
+
+// fetchGitHubTags lists version-like tags via REST, resolving commit dates and annotations.
+// Used when a repository publishes no releases and GraphQL is unavailable.
+func fetchGitHubTags(ctx context.Context, client *github.Client, owner, repo string) ([]models.Release, error) {
+	opts := &github.ListOptions{PerPage: TagsScanned}
+	githubTags, _, err := client.Repositories.ListTags(ctx, owner, repo, opts)
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+
+	ref := RepoRef{Owner: owner, Repo: repo}
+	var tags []tagInfo
+	for _, gt := range githubTags {
+		name := gt.GetName()
+		if name == "" || !version.TagPattern.MatchString(name) {
+			continue
+		}
+
+		info := tagInfo{Name: name}
+
+		// Annotated tags point at a tag object carrying the message and tagger date
+		if gitRef, _, err := client.Git.GetRef(ctx, owner, repo, "tags/"+name); err == nil && gitRef.Object.GetType() == "tag" {
+			if tagObj, _, err := client.Git.GetTag(ctx, owner, repo, gitRef.Object.GetSHA()); err == nil {
+				info.Message = strings.TrimSpace(tagObj.GetMessage())
+				if tagObj.Tagger != nil && tagObj.Tagger.Date != nil {
+					info.Date = &tagObj.Tagger.Date.Time
+				}
+			}
+		}
+
+		// Fall back to the commit date for lightweight tags
+		if info.Date == nil && gt.Commit != nil && gt.Commit.SHA != nil {
+			if commit, _, err := client.Repositories.GetCommit(ctx, owner, repo, gt.Commit.GetSHA(), nil); err == nil {
+				if date := commit.GetCommit().GetCommitter().Date; date != nil {
+					info.Date = &date.Time
+				}
+			}
+		}
+
+		tags = append(tags, info)
+		if len(tags) >= ReleasesPerRepo {
+			break
+		}
+	}
+
+	return tagReleases(ref, tags, ReleasesPerRepo), nil
+}
@@ -0,0 +1,139 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/hosttoken"
+)
+
+// MaxPages is the maximum number of pages followed via Link headers per request.
+// The pipeline exposes it as the -gitlab-pages flag.
+var MaxPages = 3
+
+// HostTokenEnv maps GitLab hosts to the environment variable holding their API token.
+// Tokens are only ever sent to their own host.
+var HostTokenEnv = map[string]string{
+	"gitlab.com":             "GITLAB_TOKEN",
+	"gitlab.gnome.org":       "GNOME_GITLAB_TOKEN",
+	"invent.kde.org":         "KDE_GITLAB_TOKEN",
+	"gitlab.freedesktop.org": "FREEDESKTOP_GITLAB_TOKEN",
+}
+
+// HostTokensEnv holds tokens for any other instance as "host=token,host=token"
+const HostTokensEnv = "GITLAB_HOST_TOKENS"
+
+// nextLinkRe extracts the rel="next" URL from a Link header
+var nextLinkRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// LoadTokens reads per-host GitLab tokens from HostTokenEnv and HostTokensEnv
+func LoadTokens() map[string]string {
+	return hosttoken.Load(HostTokenEnv, HostTokensEnv)
+}
+
+// repoHost returns the lowercased host of a repository URL (gitlab.com when missing)
+func repoHost(repoURL string) string {
+	if host := hosttoken.Host(repoURL); host != "" {
+		return host
+	}
+	return "gitlab.com"
+}
+
+// projectAPIURL builds the GitLab API v4 project URL (https://host/api/v4/projects/<encoded path>).
+// The project path comes from the URL so nested groups (World/Rust/fractal) are preserved;
+// owner/repo is only used when the URL has no path.
+func projectAPIURL(repoURL, owner, repo string) (string, error) {
+	parsedURL, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("parse repo URL: %w", err)
+	}
+
+	gitlabHost := parsedURL.Host
+	if gitlabHost == "" {
+		gitlabHost = "gitlab.com"
+	}
+
+	scheme := "https"
+	if parsedURL.Scheme == "http" {
+		scheme = "http"
+	}
+
+	// Strip "/-/releases" style suffixes and ".git"
+	projectPath := parsedURL.Path
+	if idx := strings.Index(projectPath, "/-/"); idx >= 0 {
+		projectPath = projectPath[:idx]
+	}
+	projectPath = strings.TrimSuffix(strings.Trim(projectPath, "/"), ".git")
+
+	if !strings.Contains(projectPath, "/") {
+		if owner == "" || repo == "" {
+			return "", fmt.Errorf("invalid project path in URL: %s", repoURL)
+		}
+		projectPath = owner + "/" + repo
+	}
+
+	// URL-encode the project path (GitLab requires this)
+	return fmt.Sprintf("%s://%s/api/v4/projects/%s", scheme, gitlabHost, url.PathEscape(projectPath)), nil
+}
+
+// fetchPages GETs apiURL and follows rel="next" Link headers for up to MaxPages pages,
+// passing each page body to handle until it reports it has enough results.
+// A 404 on the first page is not an error (the project has nothing to list).
+// Next links to another host are not followed, so the token never leaves its host.
+func fetchPages(ctx context.Context, token, apiURL string, handle func(io.Reader) (bool, error)) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	host := hosttoken.Host(apiURL)
+
+	for page := 0; apiURL != "" && page < MaxPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+
+		if token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("fetch page %d: %w", page+1, err)
+		}
+
+		if resp.StatusCode == 404 && page == 0 {
+			resp.Body.Close()
+			return nil
+		}
+		if resp.StatusCode != 200 {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		done, err := handle(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		apiURL = ""
+		if match := nextLinkRe.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			if hosttoken.Host(match[1]) != host {
+				log.Printf("⚠️  Not following GitLab next page link to another host: %s", match[1])
+				return nil
+			}
+			apiURL = match[1]
+		}
+	}
+
+	return nil
+}
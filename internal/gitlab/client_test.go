@@ -0,0 +1,174 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProjectAPIURL(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		owner   string
+		repo    string
+		want    string
+	}{
+		{"https://gitlab.gnome.org/GNOME/file-roller", "GNOME", "file-roller", "https://gitlab.gnome.org/api/v4/projects/GNOME%2Ffile-roller"},
+		{"https://gitlab.gnome.org/World/Rust/fractal", "World", "Rust", "https://gitlab.gnome.org/api/v4/projects/World%2FRust%2Ffractal"},
+		{"https://invent.kde.org/utilities/kate/-/releases", "", "", "https://invent.kde.org/api/v4/projects/utilities%2Fkate"},
+		{"https://gitlab.com/", "inkscape", "inkscape", "https://gitlab.com/api/v4/projects/inkscape%2Finkscape"},
+	}
+
+	for _, tt := range tests {
+		got, err := projectAPIURL(tt.repoURL, tt.owner, tt.repo)
+		if err != nil {
+			t.Errorf("projectAPIURL(%q) returned error: %v", tt.repoURL, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("projectAPIURL(%q) = %q, want %q", tt.repoURL, got, tt.want)
+		}
+	}
+}
+
+func TestFetchGitLabTagsPagination(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("Expected token to be sent, got %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		if got := r.URL.EscapedPath(); got != "/api/v4/projects/World%2FRust%2Ffractal/repository/tags" {
+			t.Errorf("Unexpected path %s", got)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			// First page has only non-version tags
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, server.URL, r.URL.EscapedPath()))
+			fmt.Fprint(w, `[{"name": "wip"}, {"name": "branch-point"}]`)
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=3>; rel="next"`, server.URL, r.URL.EscapedPath()))
+			fmt.Fprint(w, `[{"name": "9.0", "commit": {"committed_date": "2026-01-01T00:00:00Z"}}]`)
+		default:
+			t.Errorf("Requested page beyond MaxPages: %s", r.URL.RawQuery)
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	oldMaxPages := MaxPages
+	MaxPages = 2
+	defer func() { MaxPages = oldMaxPages }()
+
+	releases, err := fetchGitLabTags(context.Background(), "secret", server.URL+"/World/Rust/fractal", "World", "Rust")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if len(releases) != 1 || releases[0].Version != "9.0" {
+		t.Errorf("Expected tag from second page, got %+v", releases)
+	}
+}
+
+func TestFetchGitLabReleasesPagination(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.URL.EscapedPath(); got != "/api/v4/projects/GNOME%2Fapp/releases" {
+			t.Errorf("Unexpected path %s", got)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			// Entries without a tag don't count towards ReleasesPerRepo
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, server.URL, r.URL.EscapedPath()))
+			fmt.Fprint(w, `[{"tag_name": "3.0"}, {"tag_name": ""}, {"tag_name": "2.9"}, {"tag_name": ""}, {"tag_name": "2.8"}]`)
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=3>; rel="next"`, server.URL, r.URL.EscapedPath()))
+			fmt.Fprint(w, `[{"tag_name": "2.7"}, {"tag_name": "2.6"}, {"tag_name": "2.5"}]`)
+		default:
+			t.Errorf("Requested page after enough releases: %s", r.URL.RawQuery)
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	releases, err := fetchGitLabReleases(context.Background(), "", server.URL+"/GNOME/app", "GNOME", "app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	var versions []string
+	for _, release := range releases {
+		versions = append(versions, release.Version)
+	}
+	if want := "3.0 2.9 2.8 2.7 2.6"; strings.Join(versions, " ") != want {
+		t.Errorf("Expected releases %s, got %v", want, versions)
+	}
+}
+
+func TestFetchPagesStaysOnHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Followed next link to another host (token %q)", r.Header.Get("PRIVATE-TOKEN"))
+		fmt.Fprint(w, `[]`)
+	}))
+	defer other.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v4/projects/x/tags?page=2>; rel="next"`, other.URL))
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	pages := 0
+	err := fetchPages(context.Background(), "secret", server.URL+"/api/v4/projects/x/tags", func(io.Reader) (bool, error) {
+		pages++
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pages != 1 {
+		t.Errorf("Expected 1 page, got %d", pages)
+	}
+}
+
+func TestLoadTokens(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "com-token")
+	t.Setenv("GNOME_GITLAB_TOKEN", "gnome-token")
+	t.Setenv("KDE_GITLAB_TOKEN", "")
+	t.Setenv("FREEDESKTOP_GITLAB_TOKEN", "")
+	t.Setenv(HostTokensEnv, "gitlab.example.org=example-token, Salsa.Debian.Org=debian-token")
+
+	tokens := LoadTokens()
+	want := map[string]string{
+		"gitlab.com":         "com-token",
+		"gitlab.gnome.org":   "gnome-token",
+		"gitlab.example.org": "example-token",
+		"salsa.debian.org":   "debian-token",
+	}
+	if len(tokens) != len(want) {
+		t.Errorf("Expected %d tokens, got %v", len(want), tokens)
+	}
+	for host, token := range want {
+		if tokens[host] != token {
+			t.Errorf("tokens[%q] = %q, want %q", host, tokens[host], token)
+		}
+	}
+
+	if tokens[repoHost("https://invent.kde.org/utilities/kate")] != "" {
+		t.Error("Expected no token for a host without one")
+	}
+}
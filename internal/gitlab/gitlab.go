@@ -6,15 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
-	"net/url"
-	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
 )
 
 // GitLabRelease represents a release from GitLab API v4
@@ -24,18 +23,41 @@ type GitLabRelease struct {
 	Description string    `json:"description"`
 	ReleasedAt  time.Time `json:"released_at"`
 	CreatedAt   time.Time `json:"created_at"`
+	Upcoming    bool      `json:"upcoming_release"` // released_at is in the future
 	Links       struct {
 		Self string `json:"self"`
 	} `json:"_links"`
+	Assets struct {
+		Links []GitLabReleaseLink `json:"links"`
+	} `json:"assets"`
 }
 
+// GitLabReleaseLink is an uploaded or external file attached to a GitLab release.
+// Auto-generated source archives (assets.sources) are not included.
+type GitLabReleaseLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+	LinkType       string `json:"link_type"` // "other", "runbook", "image", "package"
+}
+
+// ReleasesPerRepo is the number of releases (or version-like tags) kept per project
+const ReleasesPerRepo = 5
+
 // EnrichWithGitLabReleases fetches GitLab releases for apps with GitLab repos
 // and adds them to the app's release list (prioritizing actual source changelogs)
 func EnrichWithGitLabReleases(apps []models.App) []models.App {
-	// Check if GitLab token is available (optional)
-	token := os.Getenv("GITLAB_TOKEN")
-	if token == "" {
-		log.Println("⚠️  No GITLAB_TOKEN found, using public API (lower rate limits)")
+	// Tokens are optional and per host (see HostTokenEnv)
+	tokens := LoadTokens()
+	if len(tokens) == 0 {
+		log.Println("⚠️  No GitLab tokens found, using public API (lower rate limits)")
+	} else {
+		hosts := make([]string, 0, len(tokens))
+		for host := range tokens {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		log.Printf("Using GitLab tokens for: %s", strings.Join(hosts, ", "))
 	}
 
 	ctx := context.Background()
@@ -58,6 +80,7 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 		wg.Add(1)
 		go func(app *models.App) {
 			defer wg.Done()
+			token := tokens[repoHost(app.SourceRepo.URL)]
 
 			releases, err := fetchGitLabReleases(ctx, token, app.SourceRepo.URL, app.SourceRepo.Owner, app.SourceRepo.Repo)
 			if err != nil {
@@ -66,6 +89,16 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 				return
 			}
 
+			// Fall back to version-like tags when the project publishes no releases
+			if len(releases) == 0 {
+				tagReleases, err := fetchGitLabTags(ctx, token, app.SourceRepo.URL, app.SourceRepo.Owner, app.SourceRepo.Repo)
+				if err != nil {
+					log.Printf("⚠️  Failed to fetch GitLab tags for %s: %v", app.SourceRepo.URL, err)
+				} else {
+					releases = tagReleases
+				}
+			}
+
 			mu.Lock()
 			// Prepend GitLab releases (they are from actual source, so prioritize them)
 			app.Releases = append(releases, app.Releases...)
@@ -85,76 +118,38 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 // fetchGitLabReleases fetches the latest releases from a GitLab repository
 // Supports both gitlab.com and self-hosted GitLab instances (like gitlab.gnome.org)
 func fetchGitLabReleases(ctx context.Context, token, repoURL, owner, repo string) ([]models.Release, error) {
-	// Parse the repository URL to extract the GitLab host and project path
-	parsedURL, err := url.Parse(repoURL)
+	projectURL, err := projectAPIURL(repoURL, owner, repo)
 	if err != nil {
-		return nil, fmt.Errorf("parse repo URL: %w", err)
+		return nil, err
 	}
 
-	gitlabHost := parsedURL.Host
-	if gitlabHost == "" {
-		gitlabHost = "gitlab.com"
-	}
+	apiURL := fmt.Sprintf("%s/releases?per_page=%d", projectURL, ReleasesPerRepo)
 
-	// Build the project path (owner/repo)
-	projectPath := owner + "/" + repo
-	if owner == "" || repo == "" {
-		// Try to extract from URL path
-		pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
-		if len(pathParts) >= 2 {
-			projectPath = strings.Join(pathParts, "/")
-		} else {
-			return nil, fmt.Errorf("invalid project path in URL: %s", repoURL)
+	// Follow pagination (up to MaxPages) until ReleasesPerRepo usable releases are collected;
+	// releases without a tag are skipped and don't count
+	var gitlabReleases []GitLabRelease
+	err = fetchPages(ctx, token, apiURL, func(body io.Reader) (bool, error) {
+		var page []GitLabRelease
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return false, fmt.Errorf("decode response: %w", err)
 		}
-	}
-
-	// URL-encode the project path (GitLab requires this)
-	encodedPath := url.PathEscape(projectPath)
-
-	// Build the API URL
-	apiURL := fmt.Sprintf("https://%s/api/v4/projects/%s/releases?per_page=5", gitlabHost, encodedPath)
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	// Add authorization header if token is provided
-	if token != "" {
-		req.Header.Set("PRIVATE-TOKEN", token)
-	}
-
-	// Make the request
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+		for _, gr := range page {
+			if gr.TagName != "" {
+				gitlabReleases = append(gitlabReleases, gr)
+			}
+		}
+		return len(page) == 0 || len(gitlabReleases) >= ReleasesPerRepo, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetch releases: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode == 404 {
-		// No releases found, not an error
-		return []models.Release{}, nil
-	}
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	var gitlabReleases []GitLabRelease
-	if err := json.NewDecoder(resp.Body).Decode(&gitlabReleases); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if len(gitlabReleases) > ReleasesPerRepo {
+		gitlabReleases = gitlabReleases[:ReleasesPerRepo]
 	}
 
 	// Convert to models.Release
-	var releases []models.Release
+	releases := []models.Release{}
 	for _, gr := range gitlabReleases {
-		if gr.TagName == "" {
-			continue
-		}
 
 		date := gr.ReleasedAt
 		if date.IsZero() {
@@ -170,7 +165,7 @@ func fetchGitLabReleases(ctx context.Context, token, repoURL, owner, repo string
 			title = gr.Name
 		}
 
-		description := markdown.ToHTML(gr.Description)
+		description := markdown.ToHTMLForRepo(gr.Description, &models.SourceRepo{Type: "gitlab", URL: repoURL, Owner: owner, Repo: repo})
 
 		// Build release URL
 		releaseURL := fmt.Sprintf("%s/-/releases/%s", strings.TrimSuffix(repoURL, ".git"), gr.TagName)
@@ -182,8 +177,28 @@ func fetchGitLabReleases(ctx context.Context, token, repoURL, owner, repo string
 			Description: description,
 			URL:         releaseURL,
 			Type:        "gitlab-release",
+			Assets:      convertGitLabAssets(gr.Assets.Links),
+			Prerelease:  gr.Upcoming || version.IsPrerelease(gr.TagName, version.Semver),
 		})
 	}
 
 	return releases, nil
 }
+
+// convertGitLabAssets converts release links to assets, preferring the permanent direct URL
+func convertGitLabAssets(links []GitLabReleaseLink) []models.ReleaseAsset {
+	var assets []models.ReleaseAsset
+	for _, link := range links {
+		assetURL := link.DirectAssetURL
+		if assetURL == "" {
+			assetURL = link.URL
+		}
+		assets = append(assets, models.ReleaseAsset{
+			Name: link.Name,
+			URL:  assetURL,
+		})
+	}
+
+	models.LinkAssetChecksums(assets)
+	return assets
+}
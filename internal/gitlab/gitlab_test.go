@@ -3,6 +3,7 @@ package gitlab
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/models"
 )
@@ -147,3 +148,51 @@ func TestEnrichWithGitLabReleases(t *testing.T) {
 		t.Error("Original release was lost during enrichment")
 	}
 }
+
+func TestConvertGitLabTags(t *testing.T) {
+	var tags []GitLabTag
+	for _, name := range []string{"47.1", "wip-branch-point", "47.0", "46.2"} {
+		tag := GitLabTag{Name: name}
+		tag.Commit.CommittedDate = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		tags = append(tags, tag)
+	}
+	tags[0].Message = "Version 47.1"
+
+	releases := convertGitLabTags(tags, "https://gitlab.gnome.org/GNOME/snapshot", 2)
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(releases))
+	}
+	if releases[0].Version != "47.1" || releases[1].Version != "47.0" {
+		t.Errorf("Unexpected versions: %s, %s", releases[0].Version, releases[1].Version)
+	}
+	if releases[0].Type != "gitlab-tag" {
+		t.Errorf("Expected type 'gitlab-tag', got '%s'", releases[0].Type)
+	}
+	if releases[0].URL != "https://gitlab.gnome.org/GNOME/snapshot/-/tags/47.1" {
+		t.Errorf("Unexpected URL: %s", releases[0].URL)
+	}
+	if releases[0].Description == "" {
+		t.Error("Expected tag annotation to become the description")
+	}
+}
+
+func TestConvertGitLabAssets(t *testing.T) {
+	links := []GitLabReleaseLink{
+		{Name: "snapshot-47.1.tar.xz", URL: "https://download.gnome.org/sources/snapshot/47/snapshot-47.1.tar.xz"},
+		{Name: "snapshot-47.1.tar.xz.sha256sum", URL: "https://example.com/raw", DirectAssetURL: "https://gitlab.gnome.org/GNOME/snapshot/-/releases/47.1/downloads/snapshot-47.1.tar.xz.sha256sum"},
+	}
+
+	assets := convertGitLabAssets(links)
+	if len(assets) != 2 {
+		t.Fatalf("Expected 2 assets, got %d", len(assets))
+	}
+	if assets[1].URL != links[1].DirectAssetURL {
+		t.Errorf("Expected direct asset URL to be preferred, got %s", assets[1].URL)
+	}
+	if assets[0].ChecksumURL != links[1].DirectAssetURL {
+		t.Errorf("Expected checksum sidecar to be linked, got %q", assets[0].ChecksumURL)
+	}
+	if assets[1].ChecksumURL != "" {
+		t.Errorf("Checksum file should not link to itself, got %q", assets[1].ChecksumURL)
+	}
+}
@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// GitLabTag represents a tag from GitLab API v4
+type GitLabTag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"` // Annotation message (empty for lightweight tags)
+	Commit  struct {
+		CommittedDate time.Time `json:"committed_date"`
+		CreatedAt     time.Time `json:"created_at"`
+	} `json:"commit"`
+	CreatedAt *time.Time `json:"created_at"` // Tag creation date (annotated tags, GitLab 16.11+)
+}
+
+// fetchGitLabTags lists version-like tags from a GitLab repository and converts them to
+// releases of type "gitlab-tag". Used when a project publishes no releases.
+func fetchGitLabTags(ctx context.Context, token, repoURL, owner, repo string) ([]models.Release, error) {
+	projectURL, err := projectAPIURL(repoURL, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := projectURL + "/repository/tags?order_by=updated&sort=desc&per_page=20"
+
+	// Follow pagination until enough version-like tags are found
+	var gitlabTags []GitLabTag
+	matched := 0
+	err = fetchPages(ctx, token, apiURL, func(body io.Reader) (bool, error) {
+		var page []GitLabTag
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return false, fmt.Errorf("decode response: %w", err)
+		}
+		for _, tag := range page {
+			if version.TagPattern.MatchString(tag.Name) {
+				matched++
+			}
+		}
+		gitlabTags = append(gitlabTags, page...)
+		return len(page) == 0 || matched >= ReleasesPerRepo, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch tags: %w", err)
+	}
+
+	return convertGitLabTags(gitlabTags, repoURL, ReleasesPerRepo), nil
+}
+
+// convertGitLabTags converts version-like tags into releases, keeping at most limit entries
+func convertGitLabTags(tags []GitLabTag, repoURL string, limit int) []models.Release {
+	releases := []models.Release{}
+
+	for _, tag := range tags {
+		if tag.Name == "" || !version.TagPattern.MatchString(tag.Name) {
+			continue
+		}
+
+		// Prefer the tag's own date, then the commit date
+		var date time.Time
+		if tag.CreatedAt != nil {
+			date = *tag.CreatedAt
+		}
+		if date.IsZero() {
+			date = tag.Commit.CommittedDate
+		}
+		if date.IsZero() {
+			date = tag.Commit.CreatedAt
+		}
+		if date.IsZero() {
+			date = time.Now()
+			log.Printf("⚠️  GitLab tag %s for %s has no date, using current time", tag.Name, repoURL)
+		}
+
+		releases = append(releases, models.Release{
+			Version:     tag.Name,
+			Date:        date,
+			Title:       tag.Name,
+			Description: markdown.ToHTMLForRepo(strings.TrimSpace(tag.Message), &models.SourceRepo{Type: "gitlab", URL: repoURL}),
+			URL:         fmt.Sprintf("%s/-/tags/%s", strings.TrimSuffix(repoURL, ".git"), url.PathEscape(tag.Name)),
+			Type:        "gitlab-tag",
+			Prerelease:  version.IsPrerelease(tag.Name, version.Semver),
+		})
+
+		if len(releases) >= limit {
+			break
+		}
+	}
+
+	return releases
+}
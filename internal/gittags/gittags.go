@@ -0,0 +1,273 @@
+// Package gittags builds release history from version-like tags of repositories that aren't
+// hosted on GitHub, GitLab or Gitea: SourceHut (git.sr.ht) and any git remote that speaks
+// the smart-HTTP protocol (cgit, gitweb, plain git-http-backend).
+package gittags
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/rss"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// ReleasesPerRepo is the number of tags kept per repository
+const ReleasesPerRepo = 5
+
+// sourceHutHost is the SourceHut git host; its refs feed lists tags with dates and annotations
+const sourceHutHost = "git.sr.ht"
+
+// EnrichWithGitTags adds tag-based releases to apps whose source repo is a SourceHut or
+// other git URL (SourceRepo type "other", or "git" via source-overrides.json)
+func EnrichWithGitTags(apps []models.App) []models.App {
+	ctx := context.Background()
+	client := &http.Client{Timeout: 10 * time.Second}
+	feeds := rss.NewParser(10 * time.Second)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	enrichedApps := make([]models.App, len(apps))
+	copy(enrichedApps, apps)
+
+	for i := range enrichedApps {
+		app := &enrichedApps[i]
+		if app.SourceRepo == nil || !isGitSource(app.SourceRepo) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *models.App) {
+			defer wg.Done()
+			repoURL := app.SourceRepo.URL
+
+			var releases []models.Release
+			var err error
+			if isSourceHut(repoURL) {
+				releases, err = fetchSourceHutTags(ctx, feeds, repoURL)
+			} else {
+				releases, err = fetchRemoteTags(ctx, client, feeds, repoURL, app.Releases)
+			}
+			if err != nil {
+				log.Printf("⚠️  Failed to list git tags for %s: %v", repoURL, err)
+				return
+			}
+			if len(releases) == 0 {
+				return
+			}
+
+			mu.Lock()
+			// Prepend tag releases (they are from actual source, so prioritize them)
+			app.Releases = append(releases, app.Releases...)
+			log.Printf("✅ Added %d git tag releases for %s", len(releases), app.ID)
+			mu.Unlock()
+		}(app)
+	}
+
+	wg.Wait()
+	return enrichedApps
+}
+
+// isGitSource reports whether a source repo should be read as a plain git remote
+func isGitSource(repo *models.SourceRepo) bool {
+	if repo.URL == "" {
+		return false
+	}
+	switch repo.Type {
+	case "git":
+		return true
+	case "other":
+		return looksLikeGitURL(repo.URL)
+	}
+	return false
+}
+
+// looksLikeGitURL reports whether a homepage URL is likely a git repository rather than a website
+// (SourceHut, git.* hosts, cgit instances or URLs ending in .git)
+func looksLikeGitURL(repoURL string) bool {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Host)
+	path := strings.TrimSuffix(parsed.Path, "/")
+	return host == sourceHutHost ||
+		strings.HasPrefix(host, "git.") ||
+		strings.Contains(host, "cgit") ||
+		strings.Contains(path, "/cgit") ||
+		strings.HasSuffix(path, ".git")
+}
+
+// isSourceHut reports whether a URL is a git.sr.ht repository
+func isSourceHut(repoURL string) bool {
+	parsed, err := url.Parse(repoURL)
+	return err == nil && strings.EqualFold(parsed.Host, sourceHutHost)
+}
+
+// fetchSourceHutTags reads tags from a SourceHut repository's refs feed
+// (https://git.sr.ht/~owner/repo/refs/rss.xml), which needs no API token
+func fetchSourceHutTags(ctx context.Context, feeds *rss.Parser, repoURL string) ([]models.Release, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse repo URL: %w", err)
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 2 || !strings.HasPrefix(segments[0], "~") {
+		return nil, fmt.Errorf("invalid SourceHut repository URL: %s", repoURL)
+	}
+	base := fmt.Sprintf("%s://%s/%s/%s", parsed.Scheme, parsed.Host, segments[0], strings.TrimSuffix(segments[1], ".git"))
+
+	feed, err := feeds.FetchAndParse(ctx, base+"/refs/rss.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []models.Release{}
+	for _, item := range feed.Items {
+		name := strings.TrimSpace(item.Title)
+		if name == "" || !version.TagPattern.MatchString(name) {
+			continue
+		}
+
+		var date time.Time
+		if item.PublishedParsed != nil {
+			date = *item.PublishedParsed
+		} else if item.UpdatedParsed != nil {
+			date = *item.UpdatedParsed
+		} else {
+			continue
+		}
+
+		link := item.Link
+		if link == "" {
+			link = base + "/refs/" + url.PathEscape(name)
+		}
+
+		releases = append(releases, models.Release{
+			Version:     name,
+			Date:        date,
+			Title:       name,
+			Description: markdown.ToHTML(strings.TrimSpace(item.Description)),
+			URL:         link,
+			Type:        "git-tag",
+			Prerelease:  version.IsPrerelease(name, version.Semver),
+		})
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date.After(releases[j].Date)
+	})
+	if len(releases) > ReleasesPerRepo {
+		releases = releases[:ReleasesPerRepo]
+	}
+	return releases, nil
+}
+
+// fetchRemoteTags lists tags over smart HTTP and keeps the highest versions. The ref
+// listing carries no dates, so each tag is dated from the cgit per-ref Atom feed when the
+// remote is a cgit instance (probed once, with the highest tag), or from an existing release
+// of the same version (e.g., AppStream). Tags without a known date are skipped, since an
+// invented date would present old tags as new releases.
+func fetchRemoteTags(ctx context.Context, client *http.Client, feeds *rss.Parser, repoURL string, existing []models.Release) ([]models.Release, error) {
+	tags, err := listRemoteTags(ctx, client, repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, tag := range tags {
+		if version.TagPattern.MatchString(tag.Name) {
+			names = append(names, tag.Name)
+		}
+	}
+	sortByVersionDesc(names)
+	if len(names) > ReleasesPerRepo {
+		names = names[:ReleasesPerRepo]
+	}
+
+	knownDates := make(map[string]time.Time)
+	for _, release := range existing {
+		if v, ok := version.Parse(release.Version); ok && !release.Date.IsZero() {
+			knownDates[v.Normalized()] = release.Date
+		}
+	}
+
+	base := strings.TrimSuffix(repoURL, "/")
+	releases := []models.Release{}
+	cgit, undated := false, 0
+	for i, name := range names {
+		var date time.Time
+		dated := false
+		if i == 0 || cgit {
+			// Only cgit serves per-ref feeds; other remotes would fail one request per tag
+			date, dated = cgitTagDate(ctx, feeds, base, name)
+			cgit = cgit || (i == 0 && dated)
+		}
+
+		link := base
+		if cgit {
+			link = fmt.Sprintf("%s/tag/?h=%s", base, url.QueryEscape(name))
+		}
+		if !dated {
+			if v, ok := version.Parse(name); ok {
+				date, dated = knownDates[v.Normalized()]
+			}
+		}
+		if !dated {
+			undated++
+			continue
+		}
+
+		releases = append(releases, models.Release{
+			Version:    name,
+			Date:       date,
+			Title:      name,
+			URL:        link,
+			Type:       "git-tag",
+			Prerelease: version.IsPrerelease(name, version.Semver),
+		})
+	}
+
+	if undated > 0 {
+		log.Printf("⚠️  Skipped %d git tags of %s without a known date", undated, repoURL)
+	}
+	return releases, nil
+}
+
+// cgitTagDate returns the date of the commit a tag points at, using cgit's Atom log feed
+// for that ref ({repo}/atom/?h=<tag>). Returns false for remotes that aren't cgit.
+func cgitTagDate(ctx context.Context, feeds *rss.Parser, repoURL, tag string) (time.Time, bool) {
+	feed, err := feeds.FetchAndParse(ctx, fmt.Sprintf("%s/atom/?h=%s", repoURL, url.QueryEscape(tag)))
+	if err != nil || len(feed.Items) == 0 {
+		return time.Time{}, false
+	}
+
+	item := feed.Items[0]
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed, true
+	}
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed, true
+	}
+	return time.Time{}, false
+}
+
+// sortByVersionDesc sorts tag names by version, highest first (pre-releases below their final release)
+func sortByVersionDesc(names []string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		return version.Compare(names[i], names[j]) > 0
+	})
+}
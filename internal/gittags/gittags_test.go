@@ -0,0 +1,188 @@
+package gittags
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/rss"
+)
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>repo</title>
+  <entry><title>Release 2.1.0</title><updated>2026-02-03T10:00:00Z</updated></entry>
+</feed>`
+
+func TestFetchRemoteTagsV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/cgit/repo/info/refs":
+			if r.Header.Get("Git-Protocol") != "version=2" {
+				t.Errorf("Expected protocol v2 request")
+			}
+			w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+			fmt.Fprint(w, encodePktLine("# service=git-upload-pack\n")+"0000"+
+				encodePktLine("version 2\n")+encodePktLine("ls-refs=unborn\n")+encodePktLine("fetch=shallow\n")+"0000")
+		case r.URL.Path == "/cgit/repo/git-upload-pack":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "command=ls-refs") || !strings.Contains(string(body), "ref-prefix refs/tags/") {
+				t.Errorf("Unexpected ls-refs request: %q", body)
+			}
+			w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+			fmt.Fprint(w, encodePktLine("1111111111111111111111111111111111111111 refs/tags/v1.9.0\n")+
+				encodePktLine("2222222222222222222222222222222222222222 refs/tags/v2.1.0 peeled:3333333333333333333333333333333333333333\n")+
+				encodePktLine("4444444444444444444444444444444444444444 refs/tags/snapshot-foo\n")+
+				encodePktLine("5555555555555555555555555555555555555555 refs/tags/v2.0.0\n")+"0000")
+		case r.URL.Path == "/cgit/repo/atom/" && r.URL.Query().Get("h") == "v2.1.0":
+			fmt.Fprint(w, atomFeed)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	existing := []models.Release{
+		{Version: "2.0.0", Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), Type: "appstream"},
+	}
+
+	releases, err := fetchRemoteTags(context.Background(), server.Client(), rss.NewParser(5*time.Second), server.URL+"/cgit/repo", existing)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// v1.9.0 has neither a cgit feed nor a matching release, so it is skipped
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d: %+v", len(releases), releases)
+	}
+	if releases[0].Version != "v2.1.0" || releases[0].Date.Format("2006-01-02") != "2026-02-03" {
+		t.Errorf("Expected v2.1.0 dated from cgit feed, got %+v", releases[0])
+	}
+	if releases[0].URL != server.URL+"/cgit/repo/tag/?h=v2.1.0" || releases[0].Type != "git-tag" {
+		t.Errorf("Unexpected cgit release: %+v", releases[0])
+	}
+	if releases[1].Version != "v2.0.0" || !releases[1].Date.Equal(existing[0].Date) {
+		t.Errorf("Expected v2.0.0 dated from the AppStream release, got %+v", releases[1])
+	}
+}
+
+func TestFetchRemoteTagsNotCgit(t *testing.T) {
+	feedRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repo.git/info/refs":
+			w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+			fmt.Fprint(w, encodePktLine("# service=git-upload-pack\n")+"0000"+
+				encodePktLine("1111111111111111111111111111111111111111 HEAD\x00multi_ack\n")+
+				encodePktLine("2222222222222222222222222222222222222222 refs/tags/1.0\n")+
+				encodePktLine("3333333333333333333333333333333333333333 refs/tags/1.1\n")+
+				encodePktLine("4444444444444444444444444444444444444444 refs/tags/1.2\n")+"0000")
+		case "/repo.git/atom/":
+			feedRequests++
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	existing := []models.Release{
+		{Version: "1.1", Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Type: "appstream"},
+	}
+
+	releases, err := fetchRemoteTags(context.Background(), server.Client(), rss.NewParser(5*time.Second), server.URL+"/repo.git", existing)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if feedRequests != 1 {
+		t.Errorf("Expected one cgit probe, got %d feed requests", feedRequests)
+	}
+	if len(releases) != 1 || releases[0].Version != "1.1" || releases[0].URL != server.URL+"/repo.git" {
+		t.Errorf("Expected only the dated 1.1 tag, linking to the repo, got %+v", releases)
+	}
+}
+
+func TestListRemoteTagsV1(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo.git/info/refs" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		fmt.Fprint(w, encodePktLine("# service=git-upload-pack\n")+"0000"+
+			encodePktLine("1111111111111111111111111111111111111111 HEAD\x00multi_ack side-band-64k\n")+
+			encodePktLine("2222222222222222222222222222222222222222 refs/heads/main\n")+
+			encodePktLine("3333333333333333333333333333333333333333 refs/tags/1.0\n")+
+			encodePktLine("4444444444444444444444444444444444444444 refs/tags/1.0^{}\n")+"0000")
+	}))
+	defer server.Close()
+
+	tags, err := listRemoteTags(context.Background(), server.Client(), server.URL+"/repo.git")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "1.0" || tags[0].OID != "3333333333333333333333333333333333333333" {
+		t.Errorf("Unexpected tags: %+v", tags)
+	}
+}
+
+func TestListRemoteTagsNotGit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html>project homepage</html>")
+	}))
+	defer server.Close()
+
+	if _, err := listRemoteTags(context.Background(), server.Client(), server.URL); err == nil {
+		t.Error("Expected error for a non-git URL")
+	}
+}
+
+func TestFetchSourceHutTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/~owner/repo/refs/rss.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>~owner/repo refs</title>
+  <item><title>0.9.0</title><link>https://git.sr.ht/~owner/repo/refs/0.9.0</link><description>Older release</description><pubDate>Mon, 01 Dec 2025 12:00:00 +0000</pubDate></item>
+  <item><title>1.0.0</title><link>https://git.sr.ht/~owner/repo/refs/1.0.0</link><description>First stable release</description><pubDate>Tue, 03 Feb 2026 12:00:00 +0000</pubDate></item>
+  <item><title>wip</title><pubDate>Wed, 04 Feb 2026 12:00:00 +0000</pubDate></item>
+</channel></rss>`)
+	}))
+	defer server.Close()
+
+	releases, err := fetchSourceHutTags(context.Background(), rss.NewParser(5*time.Second), server.URL+"/~owner/repo/tree")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d: %+v", len(releases), releases)
+	}
+	if releases[0].Version != "1.0.0" || !strings.Contains(releases[0].Description, "First stable release") {
+		t.Errorf("Expected newest tag first with annotation, got %+v", releases[0])
+	}
+}
+
+func TestLooksLikeGitURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://git.sr.ht/~owner/repo":              true,
+		"https://git.kernel.org/pub/scm/foo/foo.git": true,
+		"https://example.org/cgit/foo":               true,
+		"https://apps.gnome.org/Loupe":               false,
+		"https://example.org":                        false,
+	}
+	for repoURL, want := range tests {
+		if got := looksLikeGitURL(repoURL); got != want {
+			t.Errorf("looksLikeGitURL(%q) = %v, want %v", repoURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package gittags
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxAdvertisementSize caps how much of a ref advertisement is read (large repos list many refs)
+const maxAdvertisementSize = 8 << 20
+
+// remoteTag is a tag listed by a git remote
+type remoteTag struct {
+	Name string // Tag name without "refs/tags/"
+	OID  string // Object the tag ref points at (tag object for annotated tags)
+}
+
+// pktLine is one decoded pkt-line. Flush ("0000") and delimiter ("0001") packets are
+// represented with the flush/delim flags and no data.
+type pktLine struct {
+	data  []byte
+	flush bool
+	delim bool
+}
+
+// encodePktLine encodes a single data pkt-line
+func encodePktLine(s string) string {
+	return fmt.Sprintf("%04x%s", len(s)+4, s)
+}
+
+// readPktLines decodes a stream of pkt-lines
+func readPktLines(r io.Reader) ([]pktLine, error) {
+	reader := bufio.NewReader(r)
+	var lines []pktLine
+
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return lines, nil
+			}
+			return lines, fmt.Errorf("read pkt-line header: %w", err)
+		}
+
+		length, err := strconv.ParseUint(string(header), 16, 16)
+		if err != nil {
+			return lines, fmt.Errorf("invalid pkt-line length %q", header)
+		}
+
+		switch {
+		case length == 0:
+			lines = append(lines, pktLine{flush: true})
+			continue
+		case length == 1:
+			lines = append(lines, pktLine{delim: true})
+			continue
+		case length < 4:
+			return lines, fmt.Errorf("invalid pkt-line length %d", length)
+		}
+
+		data := make([]byte, length-4)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return lines, fmt.Errorf("read pkt-line: %w", err)
+		}
+		lines = append(lines, pktLine{data: data})
+	}
+}
+
+// listRemoteTags lists the tags of a git remote over the smart-HTTP protocol.
+// Uses protocol v2 ls-refs when the server supports it and parses the v1 ref
+// advertisement otherwise. Peeled entries ("v1.0^{}") are skipped.
+func listRemoteTags(ctx context.Context, client *http.Client, repoURL string) ([]remoteTag, error) {
+	repoURL = strings.TrimSuffix(repoURL, "/")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", repoURL+"/info/refs?service=git-upload-pack", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Git-Protocol", "version=2")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch refs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("refs returned status %d", resp.StatusCode)
+	}
+
+	// Dumb HTTP servers and web pages don't answer with an upload-pack advertisement
+	if resp.Header.Get("Content-Type") != "application/x-git-upload-pack-advertisement" {
+		return nil, fmt.Errorf("not a smart-HTTP git remote")
+	}
+
+	lines, err := readPktLines(io.LimitReader(resp.Body, maxAdvertisementSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if supportsLsRefs(lines) {
+		return lsRefs(ctx, client, repoURL)
+	}
+	return parseAdvertisement(lines), nil
+}
+
+// supportsLsRefs reports whether a protocol v2 capability advertisement includes ls-refs
+func supportsLsRefs(lines []pktLine) bool {
+	v2 := false
+	for _, line := range lines {
+		text := strings.TrimSpace(string(line.data))
+		if text == "version 2" {
+			v2 = true
+		}
+		if v2 && (text == "ls-refs" || strings.HasPrefix(text, "ls-refs=")) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAdvertisement extracts tags from a protocol v1 ref advertisement
+// ("<oid> refs/tags/v1.0\0<capabilities>" on the first line, "<oid> <ref>" afterwards)
+func parseAdvertisement(lines []pktLine) []remoteTag {
+	var tags []remoteTag
+	for _, line := range lines {
+		text := string(line.data)
+		if idx := strings.IndexByte(text, 0); idx >= 0 {
+			text = text[:idx]
+		}
+		if tag, ok := parseRefLine(strings.TrimSpace(text)); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// lsRefs runs the protocol v2 ls-refs command restricted to refs/tags/
+func lsRefs(ctx context.Context, client *http.Client, repoURL string) ([]remoteTag, error) {
+	var body bytes.Buffer
+	body.WriteString(encodePktLine("command=ls-refs\n"))
+	body.WriteString("0001")
+	body.WriteString(encodePktLine("ref-prefix refs/tags/\n"))
+	body.WriteString("0000")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", repoURL+"/git-upload-pack", &body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-git-upload-pack-request")
+	req.Header.Set("Accept", "application/x-git-upload-pack-result")
+	req.Header.Set("Git-Protocol", "version=2")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ls-refs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ls-refs returned status %d", resp.StatusCode)
+	}
+
+	lines, err := readPktLines(io.LimitReader(resp.Body, maxAdvertisementSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []remoteTag
+	for _, line := range lines {
+		if line.flush {
+			break
+		}
+		// "<oid> <ref> [symref-target:...] [peeled:...]"
+		if tag, ok := parseRefLine(strings.TrimSpace(string(line.data))); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// parseRefLine parses "<oid> refs/tags/<name> [attributes...]", skipping peeled "^{}" refs
+func parseRefLine(line string) (remoteTag, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.HasPrefix(fields[1], "refs/tags/") || strings.HasSuffix(fields[1], "^{}") {
+		return remoteTag{}, false
+	}
+	return remoteTag{Name: strings.TrimPrefix(fields[1], "refs/tags/"), OID: fields[0]}, true
+}
@@ -0,0 +1,40 @@
+// Package hosttoken reads per-host API tokens for self-hostable forges (GitLab, Gitea/Forgejo)
+// so that each token is only ever sent to its own host.
+package hosttoken
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Load reads tokens from the environment variables named in hostEnv (host -> variable) and
+// from hostsEnv, which holds tokens for any other instance as "host=token,host=token".
+// Hosts are lowercased.
+func Load(hostEnv map[string]string, hostsEnv string) map[string]string {
+	tokens := make(map[string]string)
+
+	for host, env := range hostEnv {
+		if token := os.Getenv(env); token != "" {
+			tokens[strings.ToLower(host)] = token
+		}
+	}
+
+	for _, entry := range strings.Split(os.Getenv(hostsEnv), ",") {
+		host, token, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && host != "" && token != "" {
+			tokens[strings.ToLower(host)] = token
+		}
+	}
+
+	return tokens
+}
+
+// Host returns the lowercased host (with port) of a URL, or "" when it has none
+func Host(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsedURL.Host)
+}
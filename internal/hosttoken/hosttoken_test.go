@@ -0,0 +1,45 @@
+package hosttoken
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	t.Setenv("TEST_COM_TOKEN", "com-token")
+	t.Setenv("TEST_KDE_TOKEN", "")
+	t.Setenv("TEST_HOST_TOKENS", "gitlab.example.org=example-token, Salsa.Debian.Org=debian-token,broken")
+
+	tokens := Load(map[string]string{
+		"gitlab.com":     "TEST_COM_TOKEN",
+		"invent.kde.org": "TEST_KDE_TOKEN",
+	}, "TEST_HOST_TOKENS")
+	want := map[string]string{
+		"gitlab.com":         "com-token",
+		"gitlab.example.org": "example-token",
+		"salsa.debian.org":   "debian-token",
+	}
+	if len(tokens) != len(want) {
+		t.Errorf("Expected %d tokens, got %v", len(want), tokens)
+	}
+	for host, token := range want {
+		if tokens[host] != token {
+			t.Errorf("tokens[%q] = %q, want %q", host, tokens[host], token)
+		}
+	}
+
+	if tokens[Host("https://invent.kde.org/utilities/kate")] != "" {
+		t.Error("Expected no token for a host without one")
+	}
+}
+
+func TestHost(t *testing.T) {
+	tests := map[string]string{
+		"https://Codeberg.org/owner/repo":    "codeberg.org",
+		"http://localhost:3000/api/v1/repos": "localhost:3000",
+		"owner/repo":                         "",
+		"://bad":                             "",
+	}
+	for input, want := range tests {
+		if got := Host(input); got != want {
+			t.Errorf("Host(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -1,14 +1,22 @@
 package markdown
 
 import (
+	"github.com/castrojo/bluefin-releases/internal/models"
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 )
 
 // ToHTML converts markdown text to HTML
-// Uses GitHub Flavored Markdown extensions for compatibility
+// Uses GitHub Flavored Markdown extensions for compatibility; raw HTML in the input is sanitized
 func ToHTML(md string) string {
+	return ToHTMLForRepo(md, nil)
+}
+
+// ToHTMLForRepo converts markdown text from a repository's release notes to HTML. For GitHub
+// and GitLab repositories, relative links and images point into the repository, and issue
+// (#123), merge request (!45), user (@name) and commit references become links.
+func ToHTMLForRepo(md string, repo *models.SourceRepo) string {
 	// Handle empty input
 	if md == "" {
 		return ""
@@ -19,12 +27,20 @@ func ToHTML(md string) string {
 	p := parser.NewWithExtensions(extensions)
 	doc := p.Parse([]byte(md))
 
+	ctx := newRepoContext(repo)
+	if ctx != nil {
+		ctx.rewrite(doc)
+	}
+
 	// Create HTML renderer with safe options
 	htmlFlags := html.CommonFlags | html.HrefTargetBlank
 	opts := html.RendererOptions{Flags: htmlFlags}
 	renderer := html.NewRenderer(opts)
 
-	// Render markdown to HTML
+	// Render markdown to HTML, keeping only allowlisted tags from raw HTML in the input
 	htmlBytes := markdown.Render(doc, renderer)
-	return string(htmlBytes)
+	if ctx != nil {
+		return sanitize(string(htmlBytes), ctx.resolve)
+	}
+	return Sanitize(string(htmlBytes))
 }
@@ -0,0 +1,264 @@
+package markdown
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// forge describes how a repository host links files and references
+type forge struct {
+	blob, raw string      // Path segments for files and raw files ("blob", "-/blob")
+	uploads   bool        // Root-relative /uploads/ paths belong to the project (GitLab)
+	refs      []reference // Autolinked references in text
+}
+
+// reference is a kind of text reference (issue, merge request, user, commit) and how to link it
+type reference struct {
+	re *regexp.Regexp // Group 1 is the text before the reference, which is not linked
+	// link returns the URL and link text for a match, or "" to leave it as text
+	link func(ctx *repoContext, m []string) (href, text string)
+}
+
+// repoContext is the repository a Markdown document belongs to
+type repoContext struct {
+	forge *forge
+	repo  string // Repository URL ("https://github.com/owner/repo")
+	host  string // Host URL ("https://github.com")
+}
+
+// Reference patterns. Go regexps have no lookbehind, so group 1 captures the preceding
+// character (or start of text) to keep references out of words, paths and URLs.
+var (
+	githubIssueRe   = regexp.MustCompile(`(^|[^\w/#&.-])(?:([\w.-]+/[\w.-]+))?#(\d+)\b`)
+	gitlabIssueRe   = regexp.MustCompile(`(^|[^\w/#&.-])([\w.-]+(?:/[\w.-]+)+)?([#!])(\d+)\b`)
+	githubUserRe    = regexp.MustCompile(`(^|[^\w/@.+-])@([A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38})\b`)
+	gitlabUserRe    = regexp.MustCompile(`(^|[^\w/@.+-])@(\w(?:[\w.-]*\w)?)`)
+	commitRe        = regexp.MustCompile(`(^|[^\w/@#.-])([0-9a-f]{7,40})\b`)
+	commitHasDigit  = regexp.MustCompile(`\d`)
+	commitHasLetter = regexp.MustCompile(`[a-f]`)
+)
+
+var githubForge = &forge{
+	blob: "blob",
+	raw:  "raw",
+	refs: []reference{
+		{githubIssueRe, func(ctx *repoContext, m []string) (string, string) {
+			repo := ctx.repo
+			if m[2] != "" {
+				repo = ctx.host + "/" + m[2]
+			}
+			return repo + "/issues/" + m[3], m[2] + "#" + m[3]
+		}},
+		{githubUserRe, func(ctx *repoContext, m []string) (string, string) {
+			return ctx.host + "/" + m[2], "@" + m[2]
+		}},
+		{commitRe, commitLink("/commit/")},
+	},
+}
+
+var gitlabForge = &forge{
+	blob:    "-/blob",
+	raw:     "-/raw",
+	uploads: true,
+	refs: []reference{
+		{gitlabIssueRe, func(ctx *repoContext, m []string) (string, string) {
+			repo := ctx.repo
+			if m[2] != "" {
+				repo = ctx.host + "/" + m[2]
+			}
+			kind := "/-/issues/"
+			if m[3] == "!" {
+				kind = "/-/merge_requests/"
+			}
+			return repo + kind + m[4], m[2] + m[3] + m[4]
+		}},
+		{gitlabUserRe, func(ctx *repoContext, m []string) (string, string) {
+			return ctx.host + "/" + m[2], "@" + m[2]
+		}},
+		{commitRe, commitLink("/-/commit/")},
+	},
+}
+
+// commitLink links a commit SHA, shown shortened. Hex strings without a digit are words
+// ("defaced"), and without a letter are numbers; neither is linked.
+func commitLink(prefix string) func(ctx *repoContext, m []string) (string, string) {
+	return func(ctx *repoContext, m []string) (string, string) {
+		sha := m[2]
+		if !commitHasDigit.MatchString(sha) || !commitHasLetter.MatchString(sha) {
+			return "", ""
+		}
+		return ctx.repo + prefix + sha, sha[:7]
+	}
+}
+
+// newRepoContext returns the linking context of a GitHub or GitLab repository, or nil
+// for other repositories
+func newRepoContext(repo *models.SourceRepo) *repoContext {
+	if repo == nil {
+		return nil
+	}
+
+	var f *forge
+	switch repo.Type {
+	case "github":
+		f = githubForge
+	case "gitlab":
+		f = gitlabForge
+	default:
+		return nil
+	}
+
+	repoURL := strings.TrimSuffix(strings.TrimSuffix(repo.URL, "/"), ".git")
+	if repoURL == "" && repo.Type == "github" && repo.Owner != "" && repo.Repo != "" {
+		repoURL = "https://github.com/" + repo.Owner + "/" + repo.Repo
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil
+	}
+
+	return &repoContext{forge: f, repo: repoURL, host: u.Scheme + "://" + u.Host}
+}
+
+// resolve makes a link or image URL found in the repository's Markdown absolute.
+// Relative paths point to files on the default branch; root-relative paths to the host
+// (or, for GitLab /uploads/, to the project). Absolute URLs and fragments are unchanged.
+func (ctx *repoContext) resolve(dest string, image bool) string {
+	dest = strings.TrimSpace(dest)
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "//") {
+		return dest
+	}
+	if u, err := url.Parse(dest); err != nil || u.Scheme != "" {
+		return dest
+	}
+
+	if strings.HasPrefix(dest, "/") {
+		if ctx.forge.uploads && strings.HasPrefix(dest, "/uploads/") {
+			return ctx.repo + dest
+		}
+		return ctx.host + dest
+	}
+
+	segment := ctx.forge.blob
+	if image {
+		segment = ctx.forge.raw
+	}
+	file := strings.TrimLeft(path.Clean("/"+dest), "/")
+	return ctx.repo + "/" + segment + "/HEAD/" + file
+}
+
+// rewrite makes link and image destinations absolute and autolinks references in text
+func (ctx *repoContext) rewrite(doc ast.Node) {
+	var texts []*ast.Text
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			n.Destination = []byte(ctx.resolve(string(n.Destination), false))
+			return ast.SkipChildren
+		case *ast.Image:
+			n.Destination = []byte(ctx.resolve(string(n.Destination), true))
+			return ast.SkipChildren
+		case *ast.Text:
+			texts = append(texts, n)
+		}
+		return ast.GoToNext
+	})
+
+	for _, text := range mergeAdjacent(texts) {
+		ctx.autolink(text)
+	}
+}
+
+// mergeAdjacent joins consecutive text siblings, which the parser splits at characters
+// that may start inline syntax ("!45" arrives as "!" and "45")
+func mergeAdjacent(texts []*ast.Text) []*ast.Text {
+	var result []*ast.Text
+	for _, text := range texts {
+		if n := len(result); n > 0 && ast.GetNextNode(result[n-1]) == ast.Node(text) {
+			result[n-1].Literal = append(result[n-1].Literal, text.Literal...)
+			ast.RemoveFromTree(text)
+			continue
+		}
+		result = append(result, text)
+	}
+	return result
+}
+
+// linkSpan is a reference found in a text node
+type linkSpan struct {
+	start, end int
+	href, text string
+}
+
+// autolink replaces references in a text node with links
+func (ctx *repoContext) autolink(text *ast.Text) {
+	literal := string(text.Literal)
+
+	var spans []linkSpan
+	for _, ref := range ctx.forge.refs {
+		for _, idx := range ref.re.FindAllStringSubmatchIndex(literal, -1) {
+			m := make([]string, len(idx)/2)
+			for i := range m {
+				if idx[2*i] >= 0 {
+					m[i] = literal[idx[2*i]:idx[2*i+1]]
+				}
+			}
+			if href, label := ref.link(ctx, m); href != "" {
+				spans = append(spans, linkSpan{start: idx[3], end: idx[1], href: href, text: label})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	parent := text.Parent
+	var nodes []ast.Node
+	pos := 0
+	for _, span := range spans {
+		if span.start < pos {
+			continue // Overlaps an earlier reference
+		}
+		if span.start > pos {
+			nodes = append(nodes, newText(parent, literal[pos:span.start]))
+		}
+		link := &ast.Link{Destination: []byte(span.href)}
+		link.Parent = parent
+		ast.AppendChild(link, newText(nil, span.text))
+		nodes = append(nodes, link)
+		pos = span.end
+	}
+	if pos < len(literal) {
+		nodes = append(nodes, newText(parent, literal[pos:]))
+	}
+
+	// Put the new nodes where the text node was
+	var children []ast.Node
+	for _, child := range parent.GetChildren() {
+		if child == ast.Node(text) {
+			children = append(children, nodes...)
+			continue
+		}
+		children = append(children, child)
+	}
+	parent.SetChildren(children)
+}
+
+// newText returns a text node with the given parent
+func newText(parent ast.Node, literal string) *ast.Text {
+	text := &ast.Text{}
+	text.Literal = []byte(literal)
+	text.Parent = parent
+	return text
+}
@@ -0,0 +1,144 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+var (
+	githubRepo = &models.SourceRepo{Type: "github", URL: "https://github.com/owner/app", Owner: "owner", Repo: "app"}
+	gitlabRepo = &models.SourceRepo{Type: "gitlab", URL: "https://gitlab.gnome.org/GNOME/app.git", Owner: "GNOME", Repo: "app"}
+)
+
+func TestToHTMLForRepoReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     *models.SourceRepo
+		md       string
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "github issues, users and commits",
+			repo: githubRepo,
+			md:   "Fixed #12 (thanks @jane-doe!) in a1b2c3d4e5, see other/lib#9",
+			contains: []string{
+				`<a href="https://github.com/owner/app/issues/12" target="_blank" rel="noopener noreferrer">#12</a>`,
+				`<a href="https://github.com/jane-doe" target="_blank" rel="noopener noreferrer">@jane-doe</a>`,
+				`<a href="https://github.com/owner/app/commit/a1b2c3d4e5" target="_blank" rel="noopener noreferrer">a1b2c3d</a>`,
+				`<a href="https://github.com/other/lib/issues/9" target="_blank" rel="noopener noreferrer">other/lib#9</a>`,
+			},
+		},
+		{
+			name:     "github has no merge request references",
+			repo:     githubRepo,
+			md:       "Wow!45 times",
+			excludes: []string{"<a "},
+		},
+		{
+			name: "gitlab issues, merge requests, users and commits",
+			repo: gitlabRepo,
+			md:   "Fixed #12 and !45 by @john.doe in a1b2c3d4e5, see GNOME/gtk#99 and World/apps/tool!3",
+			contains: []string{
+				`href="https://gitlab.gnome.org/GNOME/app/-/issues/12"`,
+				`href="https://gitlab.gnome.org/GNOME/app/-/merge_requests/45"`,
+				`href="https://gitlab.gnome.org/john.doe"`,
+				`href="https://gitlab.gnome.org/GNOME/app/-/commit/a1b2c3d4e5"`,
+				`href="https://gitlab.gnome.org/GNOME/gtk/-/issues/99"`,
+				`href="https://gitlab.gnome.org/World/apps/tool/-/merge_requests/3"`,
+			},
+		},
+		{
+			name: "references in code, links, emails and words are left alone",
+			repo: githubRepo,
+			md:   "Run `git show a1b2c3d4e5 #7`, mail dev@example.org, see [#3](https://example.org/#4), issue#5, 12345678, defaced\n\n    code #8",
+			contains: []string{
+				"<code>git show a1b2c3d4e5 #7</code>",
+				"dev@example.org",
+				`<a href="https://example.org/#4" target="_blank" rel="noopener noreferrer">#3</a>`,
+				"issue#5",
+				"12345678",
+				"defaced",
+				"code #8",
+			},
+			excludes: []string{"/commit/", "/issues/", "github.com/example.org"},
+		},
+		{
+			name:     "other repositories are not linked",
+			repo:     &models.SourceRepo{Type: "gitea", URL: "https://codeberg.org/owner/app"},
+			md:       "Fixed #12 by @jane in [docs](docs/x.md)",
+			contains: []string{`<a href="docs/x.md" target="_blank" rel="noopener noreferrer">docs</a>`},
+			excludes: []string{"codeberg.org"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToHTMLForRepo(tt.md, tt.repo)
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %s\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("output contains %s\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestToHTMLForRepoRelativeURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		repo *models.SourceRepo
+		md   string
+		want []string
+	}{
+		{
+			name: "github files and images",
+			repo: githubRepo,
+			md:   "[guide](./docs/guide.md) ![shot](../assets/shot.png) [wiki](/owner/app/wiki) [top](#changes)",
+			want: []string{
+				`href="https://github.com/owner/app/blob/HEAD/docs/guide.md"`,
+				`src="https://github.com/owner/app/raw/HEAD/assets/shot.png"`,
+				`href="https://github.com/owner/app/wiki"`,
+				`href="#changes"`,
+			},
+		},
+		{
+			name: "gitlab uploads belong to the project",
+			repo: gitlabRepo,
+			md:   "![demo](/uploads/abc123/demo.webm) [news](NEWS) ![logo](data/logo.svg)",
+			want: []string{
+				`src="https://gitlab.gnome.org/GNOME/app/uploads/abc123/demo.webm"`,
+				`href="https://gitlab.gnome.org/GNOME/app/-/blob/HEAD/NEWS"`,
+				`src="https://gitlab.gnome.org/GNOME/app/-/raw/HEAD/data/logo.svg"`,
+			},
+		},
+		{
+			name: "raw HTML is rewritten and sanitized",
+			repo: githubRepo,
+			md:   `<p><img src="screenshots/main.png" onerror="alert(1)"> <a href="CHANGELOG.md">changes</a> <a href="javascript:alert(1)">x</a></p>`,
+			want: []string{
+				`<img src="https://github.com/owner/app/raw/HEAD/screenshots/main.png" />`,
+				`<a href="https://github.com/owner/app/blob/HEAD/CHANGELOG.md" rel="noopener noreferrer">changes</a>`,
+				`<a>x</a>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToHTMLForRepo(tt.md, tt.repo)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %s\n%s", want, got)
+				}
+			}
+		})
+	}
+}
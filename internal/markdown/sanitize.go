@@ -0,0 +1,243 @@
+package markdown
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedTags maps each element kept by Sanitize to the attributes it may carry.
+// Other elements are removed but their text is kept.
+var allowedTags = map[string][]string{
+	"a":          {"href", "title", "target"},
+	"abbr":       {"title"},
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       {"class"},
+	"dd":         nil,
+	"del":        nil,
+	"details":    nil,
+	"div":        nil,
+	"dl":         nil,
+	"dt":         nil,
+	"em":         nil,
+	"h1":         {"id"},
+	"h2":         {"id"},
+	"h3":         {"id"},
+	"h4":         {"id"},
+	"h5":         {"id"},
+	"h6":         {"id"},
+	"hr":         nil,
+	"i":          nil,
+	"img":        {"src", "alt", "title", "width", "height"},
+	"kbd":        nil,
+	"li":         nil,
+	"ol":         {"start"},
+	"p":          nil,
+	"pre":        nil,
+	"s":          nil,
+	"span":       nil,
+	"strong":     nil,
+	"sub":        nil,
+	"summary":    nil,
+	"sup":        nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         {"align"},
+	"th":         {"align"},
+	"thead":      nil,
+	"tr":         nil,
+	"ul":         nil,
+}
+
+// droppedTags are removed together with their content
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true,
+	"object": true, "embed": true, "applet": true, "noscript": true, "noembed": true,
+	"noframes": true, "template": true, "textarea": true, "select": true, "title": true,
+	"svg": true, "math": true, "xmp": true, "plaintext": true, "head": true,
+}
+
+// voidTags never have content or an end tag
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// linkSchemes are the URL schemes allowed in links; images are limited to http and https
+var (
+	linkSchemes  = map[string]bool{"http": true, "https": true, "mailto": true}
+	imageSchemes = map[string]bool{"http": true, "https": true}
+)
+
+// attrPatterns restrict the values of some attributes
+var attrPatterns = map[string]*regexp.Regexp{
+	"class":  regexp.MustCompile(`^language-[\w+#-]+$`), // Code block languages only
+	"target": regexp.MustCompile(`^_blank$`),
+	"start":  regexp.MustCompile(`^\d+$`),
+	"width":  regexp.MustCompile(`^\d+%?$`),
+	"height": regexp.MustCompile(`^\d+%?$`),
+	"align":  regexp.MustCompile(`^(left|center|right)$`),
+}
+
+// Sanitize reduces untrusted HTML to an allowlist of formatting tags and attributes.
+// Scripts, frames, styles and event handlers are removed, URLs are limited to safe schemes,
+// links get rel="noopener noreferrer", and unclosed elements are closed.
+func Sanitize(s string) string {
+	return sanitize(s, nil)
+}
+
+// sanitize sanitizes HTML, passing link and image URLs through resolve (when set) first
+func sanitize(s string, resolve func(dest string, image bool) string) string {
+	if s == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	var open []string
+	dropName, dropDepth := "", 0
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		name := token.Data
+
+		// Inside a dropped element only its own nesting is tracked, as other elements may be left open
+		if dropDepth > 0 {
+			switch {
+			case tt == html.StartTagToken && name == dropName:
+				dropDepth++
+			case tt == html.EndTagToken && name == dropName:
+				dropDepth--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			b.WriteString(html.EscapeString(token.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedTags[name] {
+				if tt == html.StartTagToken && !voidTags[name] {
+					dropName, dropDepth = name, 1
+				}
+				continue
+			}
+			if _, ok := allowedTags[name]; !ok {
+				continue
+			}
+			b.WriteString(startTag(token, resolve))
+			if !voidTags[name] {
+				if tt == html.SelfClosingTagToken {
+					b.WriteString("</" + name + ">")
+				} else {
+					open = append(open, name)
+				}
+			}
+
+		case html.EndTagToken:
+			// Close the element and any left open inside it; stray end tags are dropped
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// startTag renders a start tag with only its allowed attributes
+func startTag(token html.Token, resolve func(dest string, image bool) string) string {
+	var b strings.Builder
+	b.WriteString("<" + token.Data)
+
+	hasHref := false
+	for _, attr := range token.Attr {
+		if attr.Namespace != "" || !allowedAttr(token.Data, attr.Key) {
+			continue
+		}
+
+		value := attr.Val
+		if resolve != nil && (attr.Key == "href" || attr.Key == "src") {
+			value = resolve(value, attr.Key == "src")
+		}
+		switch attr.Key {
+		case "href":
+			value = safeURL(value, linkSchemes)
+			hasHref = value != ""
+		case "src":
+			value = safeURL(value, imageSchemes)
+		default:
+			if pattern, ok := attrPatterns[attr.Key]; ok && !pattern.MatchString(value) {
+				value = ""
+			}
+		}
+		if value == "" {
+			continue
+		}
+
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+	}
+
+	if hasHref {
+		b.WriteString(` rel="noopener noreferrer"`)
+	}
+	if voidTags[token.Data] {
+		b.WriteString(" />")
+	} else {
+		b.WriteString(">")
+	}
+	return b.String()
+}
+
+// allowedAttr reports whether an element may carry an attribute
+func allowedAttr(tag, attr string) bool {
+	for _, allowed := range allowedTags[tag] {
+		if allowed == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// safeURL returns the URL if it is relative or uses an allowed scheme, and "" otherwise.
+// Whitespace and control characters are removed first, as browsers ignore them ("java\tscript:").
+func safeURL(raw string, schemes map[string]bool) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+	if cleaned == "" {
+		return ""
+	}
+
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return ""
+	}
+	if u.Scheme != "" && !schemes[strings.ToLower(u.Scheme)] {
+		return ""
+	}
+	// "//host/path" and "/path" inherit the page's scheme, which is always safe
+	return cleaned
+}
@@ -0,0 +1,117 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{
+			name:  "script removed with content",
+			input: `<p>Fixed<script>alert(1)</script> crash</p>`,
+			want:  `<p>Fixed crash</p>`,
+		},
+		{
+			name:  "iframe removed with content",
+			input: `<iframe src="https://evil.example/"><p>fallback</p></iframe><p>Notes</p>`,
+			want:  `<p>Notes</p>`,
+		},
+		{
+			name:  "unclosed element inside dropped element",
+			input: `<svg><p>fallback</svg><p>Notes</p>`,
+			want:  `<p>Notes</p>`,
+		},
+		{
+			name:  "nested dropped element",
+			input: `<svg><svg></svg><li>x</svg><p>Notes</p>`,
+			want:  `<p>Notes</p>`,
+		},
+		{
+			name:  "event handlers removed",
+			input: `<img src="https://example.org/a.png" onerror="alert(1)" alt="screenshot">`,
+			want:  `<img src="https://example.org/a.png" alt="screenshot" />`,
+		},
+		{
+			name:  "javascript link dropped",
+			input: `<a href="javascript:alert(1)">click</a>`,
+			want:  `<a>click</a>`,
+		},
+		{
+			name:  "obfuscated javascript link dropped",
+			input: `<a href=" JaVa&#x09;Script&colon;alert(1)">click</a>`,
+			want:  `<a>click</a>`,
+		},
+		{
+			name:  "data image dropped",
+			input: `<img src="data:image/svg+xml;base64,PHN2Zz4=">`,
+			want:  `<img />`,
+		},
+		{
+			name:  "links get rel",
+			input: `<a href="https://example.org/" target="_blank" rel="opener">release</a>`,
+			want:  `<a href="https://example.org/" target="_blank" rel="noopener noreferrer">release</a>`,
+		},
+		{
+			name:  "relative and mailto links kept",
+			input: `<a href="/issues/1">#1</a> <a href="mailto:dev@example.org">mail</a>`,
+			want:  `<a href="/issues/1" rel="noopener noreferrer">#1</a> <a href="mailto:dev@example.org" rel="noopener noreferrer">mail</a>`,
+		},
+		{
+			name:  "unknown tags unwrapped",
+			input: `<form action="/x"><p>Text <font color="red">here</font></p></form>`,
+			want:  `<p>Text here</p>`,
+		},
+		{
+			name:  "svg removed with content",
+			input: `<svg><g onload="alert(1)"><text>x</text></g></svg><p>ok</p>`,
+			want:  `<p>ok</p>`,
+		},
+		{
+			name:  "style and class removed",
+			input: `<p style="position:fixed" class="overlay">text</p><pre><code class="language-go">x := 1</code></pre>`,
+			want:  `<p>text</p><pre><code class="language-go">x := 1</code></pre>`,
+		},
+		{
+			name:  "unclosed elements closed and stray end tags dropped",
+			input: `<ul><li><strong>New</li></div>`,
+			want:  `<ul><li><strong>New</strong></li></ul>`,
+		},
+		{
+			name:  "text escaped",
+			input: `a < b & "c"`,
+			want:  `a &lt; b &amp; &#34;c&#34;`,
+		},
+		{
+			name:  "comments removed",
+			input: `<p>a<!-- <script>alert(1)</script> -->b</p>`,
+			want:  `<p>ab</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sanitize(tt.input)
+			if got != tt.want {
+				t.Errorf("Sanitize(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+			if again := Sanitize(got); again != got {
+				t.Errorf("Sanitize is not idempotent: %q -> %q", got, again)
+			}
+		})
+	}
+}
+
+func TestToHTMLSanitizesRawHTML(t *testing.T) {
+	got := ToHTML("## Changes\n\n- Fixed <img src=x onerror=alert(1)> rendering\n\n<script>alert(1)</script>\n\n[docs](javascript:alert(1))")
+	for _, payload := range []string{"<script", "onerror", "javascript:"} {
+		if strings.Contains(got, payload) {
+			t.Errorf("ToHTML output contains %q:\n%s", payload, got)
+		}
+	}
+	if !strings.Contains(got, "<h2") || !strings.Contains(got, "<li>Fixed") {
+		t.Errorf("ToHTML lost Markdown formatting:\n%s", got)
+	}
+}
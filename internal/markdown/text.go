@@ -0,0 +1,265 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultExcerptLength is the default maximum length of an excerpt, in characters
+const DefaultExcerptLength = 280
+
+// Block kinds of rendered HTML
+const (
+	BlockHeading   = "heading"
+	BlockParagraph = "paragraph"
+	BlockItem      = "item"
+	BlockPre       = "pre"
+)
+
+// Block is a block of text from rendered HTML: a heading, paragraph, list item or preformatted text
+type Block struct {
+	Kind   string
+	Text   string
+	Depth  int    // List nesting of items (1 for top-level items)
+	Marker string // List item marker ("-" or "3.")
+}
+
+// blockTags start a new block of text
+var blockTags = map[string]string{
+	"p": BlockParagraph, "div": BlockParagraph, "blockquote": BlockParagraph, "tr": BlockParagraph,
+	"dt": BlockParagraph, "dd": BlockParagraph, "details": BlockParagraph, "summary": BlockParagraph,
+	"h1": BlockHeading, "h2": BlockHeading, "h3": BlockHeading, "h4": BlockHeading, "h5": BlockHeading, "h6": BlockHeading,
+	"li": BlockItem, "pre": BlockPre,
+}
+
+// PlainText renders HTML as plain text: paragraphs separated by blank lines, list items as
+// "- item" (indented when nested), preformatted text kept as-is, and tags dropped
+func PlainText(s string) string {
+	var b strings.Builder
+	previous := ""
+	for _, current := range Blocks(s) {
+		if previous != "" {
+			if previous == BlockItem && current.Kind == BlockItem {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+
+		switch current.Kind {
+		case BlockItem:
+			b.WriteString(strings.Repeat("  ", current.Depth-1) + current.Marker + " " + current.Text)
+		default:
+			b.WriteString(current.Text)
+		}
+		previous = current.Kind
+	}
+	return b.String()
+}
+
+// Excerpt returns a one-line summary of HTML release notes at most maxLen characters long:
+// the first paragraph (with the list it introduces, when it ends with a colon) or the first
+// list's top-level items, after any leading headings. Text that doesn't fit is cut at a word
+// boundary and ends with "…".
+func Excerpt(s string, maxLen int) string {
+	all := Blocks(s)
+	for len(all) > 0 && all[0].Kind == BlockHeading {
+		all = all[1:]
+	}
+	if len(all) == 0 {
+		return ""
+	}
+
+	var text string
+	switch first := all[0]; first.Kind {
+	case BlockItem:
+		text = strings.Join(topLevelItems(all), "; ")
+	case BlockPre:
+		text = first.Text
+	default:
+		text = first.Text
+		if items := topLevelItems(all[1:]); strings.HasSuffix(text, ":") && len(items) > 0 {
+			text += " " + strings.Join(items, "; ")
+		}
+	}
+	return truncate(strings.Join(strings.Fields(text), " "), maxLen)
+}
+
+// topLevelItems returns the texts of the top-level items of the list the blocks start with
+func topLevelItems(blocks []Block) []string {
+	var items []string
+	for _, b := range blocks {
+		if b.Kind != BlockItem {
+			break
+		}
+		if b.Depth == 1 {
+			items = append(items, b.Text)
+		}
+	}
+	return items
+}
+
+// truncate shortens text to at most maxLen characters, cutting at the last word boundary
+// (unless that loses more than half the text) and appending "…"
+func truncate(text string, maxLen int) string {
+	runes := []rune(text)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return text
+	}
+	if maxLen == 1 {
+		return "…"
+	}
+
+	cut := runes[:maxLen-1]
+	if space := lastSpace(cut); space > len(cut)/2 {
+		cut = cut[:space]
+	}
+	return strings.TrimRightFunc(string(cut), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:-–—(", r)
+	}) + "…"
+}
+
+// lastSpace returns the index of the last whitespace rune, or -1
+func lastSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// list is an open ul or ol element
+type list struct {
+	ordered bool
+	next    int // Number of the next item of an ordered list
+}
+
+// Blocks splits HTML into blocks of text in document order. Whitespace is collapsed
+// except in preformatted blocks.
+func Blocks(s string) []Block {
+	var (
+		result  []Block
+		current = Block{Kind: BlockParagraph}
+		text    strings.Builder
+		lists   []list
+		skip    = 0
+	)
+
+	flush := func() {
+		content := text.String()
+		text.Reset()
+		if current.Kind == BlockPre {
+			content = strings.Trim(content, "\n")
+		} else {
+			lines := strings.Split(content, "\n")
+			for i, line := range lines {
+				lines[i] = strings.Join(strings.Fields(line), " ")
+			}
+			content = strings.Join(nonEmpty(lines), "\n")
+		}
+		if strings.TrimSpace(content) != "" {
+			current.Text = content
+			result = append(result, current)
+		}
+		current = Block{Kind: BlockParagraph}
+	}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		name := token.Data
+
+		if skip > 0 {
+			if tt == html.EndTagToken && (name == "script" || name == "style") {
+				skip--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			text.WriteString(token.Data)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch {
+			case name == "script" || name == "style":
+				if tt == html.StartTagToken {
+					skip++
+				}
+			case name == "br":
+				text.WriteString("\n")
+			case name == "img":
+				for _, attr := range token.Attr {
+					if attr.Key == "alt" {
+						text.WriteString(attr.Val)
+					}
+				}
+			case name == "ul" || name == "ol":
+				flush()
+				lists = append(lists, list{ordered: name == "ol", next: listStart(token)})
+			case name == "td" || name == "th":
+				text.WriteString(" ")
+			case blockTags[name] == BlockParagraph && current.Kind == BlockItem && strings.TrimSpace(text.String()) == "":
+				// Paragraphs of loose list items stay part of the item
+			case blockTags[name] != "":
+				flush()
+				current.Kind = blockTags[name]
+				if current.Kind == BlockItem {
+					current.Depth, current.Marker = 1, "-"
+					if n := len(lists); n > 0 {
+						current.Depth = n
+						if lists[n-1].ordered {
+							current.Marker = strconv.Itoa(lists[n-1].next) + "."
+							lists[n-1].next++
+						}
+					}
+				}
+			}
+
+		case html.EndTagToken:
+			switch {
+			case name == "ul" || name == "ol":
+				flush()
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+			case blockTags[name] != "":
+				flush()
+			}
+		}
+	}
+	flush()
+
+	return result
+}
+
+// listStart returns the number of the first item of a list
+func listStart(token html.Token) int {
+	for _, attr := range token.Attr {
+		if attr.Key == "start" {
+			if n, err := strconv.Atoi(attr.Val); err == nil {
+				return n
+			}
+		}
+	}
+	return 1
+}
+
+// nonEmpty returns the non-empty strings
+func nonEmpty(lines []string) []string {
+	result := lines[:0]
+	for _, line := range lines {
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
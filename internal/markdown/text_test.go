@@ -0,0 +1,120 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const releaseHTML = `<h2 id="whats-changed">What's Changed</h2>
+
+<ul>
+<li>Add <strong>dark mode</strong> by <a href="https://github.com/jane">@jane</a> in <a href="https://github.com/o/r/pull/1">#1</a></li>
+<li>Fix crash on startup
+<ul>
+<li>Only on Wayland</li>
+</ul></li>
+</ul>
+
+<p>Full changelog: <a href="https://github.com/o/r/compare/v1...v2">v1...v2</a><br>
+Thanks to all contributors &amp; testers.</p>
+
+<ol start="3">
+<li><p>Loose item</p></li>
+<li>Next</li>
+</ol>
+
+<pre><code>make install
+  PREFIX=/usr
+</code></pre>
+`
+
+func TestPlainText(t *testing.T) {
+	want := strings.Join([]string{
+		"What's Changed",
+		"",
+		"- Add dark mode by @jane in #1",
+		"- Fix crash on startup",
+		"  - Only on Wayland",
+		"",
+		"Full changelog: v1...v2",
+		"Thanks to all contributors & testers.",
+		"",
+		"3. Loose item",
+		"4. Next",
+		"",
+		"make install",
+		"  PREFIX=/usr",
+	}, "\n")
+
+	if got := PlainText(releaseHTML); got != want {
+		t.Errorf("PlainText =\n%s\nwant\n%s", got, want)
+	}
+	if got := PlainText(""); got != "" {
+		t.Errorf("PlainText(\"\") = %q", got)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		html   string
+		maxLen int
+		want   string
+	}{
+		{
+			name:   "top-level bullets after a heading",
+			html:   releaseHTML,
+			maxLen: 280,
+			want:   "Add dark mode by @jane in #1; Fix crash on startup",
+		},
+		{
+			name:   "first paragraph only",
+			html:   "<p>This release brings a new editor.</p><p>Second paragraph.</p>",
+			maxLen: 280,
+			want:   "This release brings a new editor.",
+		},
+		{
+			name:   "paragraph introducing a list",
+			html:   "<p>Changes:</p><ul><li>One</li><li>Two</li></ul><p>Later</p>",
+			maxLen: 280,
+			want:   "Changes: One; Two",
+		},
+		{
+			name:   "cut at a word boundary",
+			html:   "<p>Improved performance of the thumbnail cache, and reduced memory use.</p>",
+			maxLen: 40,
+			want:   "Improved performance of the thumbnail…",
+		},
+		{
+			name:   "trailing punctuation dropped before the ellipsis",
+			html:   "<p>Fixed crashes, hangs and leaks</p>",
+			maxLen: 16,
+			want:   "Fixed crashes…",
+		},
+		{
+			name:   "never cuts inside a tag or entity",
+			html:   `<p>Tom &amp; Jerry <a href="https://example.org/very/long/url">link</a> text</p>`,
+			maxLen: 14,
+			want:   "Tom & Jerry…",
+		},
+		{
+			name:   "empty",
+			html:   "<h2>Only a heading</h2>",
+			maxLen: 280,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Excerpt(tt.html, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("Excerpt = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.maxLen {
+				t.Errorf("Excerpt is %d characters, longer than %d", n, tt.maxLen)
+			}
+		})
+	}
+}
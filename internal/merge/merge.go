@@ -0,0 +1,133 @@
+// Package merge combines releases of the same version reported by several sources
+// (e.g., a GitHub release, its tag and the AppStream entry) into a single release that
+// records every source in Release.Sources.
+package merge
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// typePriority ranks release types. The highest-ranked source of a merged release provides
+// its version, title, URL, type and pre-release flag; unknown types rank lowest.
+var typePriority = map[string]int{
+	"github-release":     4,
+	"gitlab-release":     4,
+	"gitea-release":      4,
+	"mozilla-release":    4,
+	"mozilla-esr":        4,
+	"mozilla-beta":       4,
+	"bluefin-os-release": 4,
+	"feed-release":       3,
+	"github-tag":         2,
+	"gitlab-tag":         2,
+	"gitea-tag":          2,
+	"git-tag":            2,
+	"appstream":          1,
+	"tap-update":         0,
+}
+
+// MergeReleases merges each app's releases that share a normalized version ("v1.2.0",
+// "1.2" and "app-1.2.0" all match). Releases with distinct versions are always kept.
+func MergeReleases(apps []models.App) []models.App {
+	merged := 0
+	for i := range apps {
+		app := &apps[i]
+		before := len(app.Releases)
+		app.Releases = Releases(app.Releases)
+		merged += before - len(app.Releases)
+	}
+
+	log.Printf("Merged %d duplicate release(s) across sources", merged)
+	return apps
+}
+
+// Releases merges releases with the same normalized version, keeping the position of
+// the first release of each version
+func Releases(releases []models.Release) []models.Release {
+	var keys []string
+	groups := make(map[string][]models.Release)
+
+	for i, release := range releases {
+		key := versionKey(release.Version)
+		if key == "" {
+			// Releases without a version can't be matched; keep each on its own
+			key = "#" + strconv.Itoa(i)
+		}
+		if _, seen := groups[key]; !seen {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], release)
+	}
+
+	result := make([]models.Release, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, combine(groups[key]))
+	}
+	return result
+}
+
+// versionKey returns the key releases are matched on: the normalized version, or the
+// lowercased version string when it contains no number
+func versionKey(v string) string {
+	if parsed, ok := version.Parse(v); ok {
+		return parsed.Normalized()
+	}
+	return strings.ToLower(strings.TrimSpace(v))
+}
+
+// combine merges releases of one version. The highest-priority source provides the identity
+// fields; the date comes from the highest-priority source that has one, the description is
+// the longest available, and every source is recorded in Sources.
+func combine(group []models.Release) models.Release {
+	ordered := byPriority(group)
+	result := ordered[0]
+	result.Sources = nil
+
+	for _, release := range ordered {
+		if result.Date.IsZero() {
+			result.Date = release.Date
+		}
+		if result.Title == "" {
+			result.Title = release.Title
+		}
+		if result.URL == "" {
+			result.URL = release.URL
+		}
+		if len(result.Assets) == 0 {
+			result.Assets = release.Assets
+		}
+		if len(result.Advisories) == 0 {
+			result.Advisories = release.Advisories
+		}
+		result.Security = result.Security || release.Security
+		if len(release.Description) > len(result.Description) {
+			result.Description = release.Description
+		}
+		result.Sources = append(result.Sources, sourcesOf(release)...)
+	}
+
+	return result
+}
+
+// byPriority returns the releases ordered by source priority (stable for equal priorities)
+func byPriority(group []models.Release) []models.Release {
+	ordered := append([]models.Release(nil), group...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return typePriority[ordered[i].Type] > typePriority[ordered[j].Type]
+	})
+	return ordered
+}
+
+// sourcesOf returns the provenance of a release, reusing Sources of an already merged release
+func sourcesOf(release models.Release) []models.ReleaseSource {
+	if len(release.Sources) > 0 {
+		return release.Sources
+	}
+	return []models.ReleaseSource{{Type: release.Type, URL: release.URL, Date: release.Date}}
+}
@@ -0,0 +1,96 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestReleasesMergesAcrossSources(t *testing.T) {
+	appstreamDate := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	githubDate := time.Date(2026, 2, 1, 14, 30, 0, 0, time.UTC)
+
+	releases := []models.Release{
+		{Version: "v2.1.0", Title: "v2.1.0", Type: "github-release", URL: "https://github.com/o/r/releases/tag/v2.1.0", Date: githubDate, Description: "<p>Fixes</p>",
+			Assets: []models.ReleaseAsset{{Name: "r.tar.gz"}}},
+		{Version: "2.0.0", Title: "2.0.0", Type: "github-tag", URL: "https://github.com/o/r/releases/tag/2.0.0", Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{Version: "2.1", Type: "appstream", Date: appstreamDate, Description: "<p>Fixes a crash when opening large files and updates translations</p>"},
+		{Version: "1.9.0", Type: "appstream", Date: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{Version: "2.0.0", Type: "appstream", Date: time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC), Description: "<p>New UI</p>"},
+	}
+
+	merged := Releases(releases)
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 releases (2.1, 2.0, 1.9), got %d: %+v", len(merged), merged)
+	}
+
+	latest := merged[0]
+	if latest.Type != "github-release" || latest.Version != "v2.1.0" || !latest.Date.Equal(githubDate) {
+		t.Errorf("Expected GitHub release to provide identity and date, got %+v", latest)
+	}
+	if !strings.Contains(latest.Description, "large files") {
+		t.Errorf("Expected the longest description, got %q", latest.Description)
+	}
+	if len(latest.Assets) != 1 {
+		t.Errorf("Expected assets to be kept, got %+v", latest.Assets)
+	}
+	if len(latest.Sources) != 2 || latest.Sources[0].Type != "github-release" || latest.Sources[1].Type != "appstream" {
+		t.Errorf("Unexpected sources: %+v", latest.Sources)
+	}
+
+	// Versions the repo never published stay, instead of being dropped with all appstream releases
+	if merged[1].Version != "2.0.0" || merged[1].Type != "github-tag" || merged[1].Description != "<p>New UI</p>" {
+		t.Errorf("Unexpected 2.0.0 release: %+v", merged[1])
+	}
+	if merged[2].Version != "1.9.0" || merged[2].Type != "appstream" || len(merged[2].Sources) != 1 {
+		t.Errorf("Unexpected 1.9.0 release: %+v", merged[2])
+	}
+}
+
+func TestReleasesMergesFeedReleases(t *testing.T) {
+	releases := []models.Release{
+		{Version: "24.08.0", Title: "KDE Gear 24.08.0", Type: "feed-release", URL: "https://kde.org/announcements/gear/24.08.0/",
+			Date: time.Date(2024, 8, 22, 12, 0, 0, 0, time.UTC), Description: "<p>Kate gains a new terminal panel and faster search across projects</p>"},
+		{Version: "24.08.0", Type: "appstream", Date: time.Date(2024, 8, 22, 0, 0, 0, 0, time.UTC), Description: "<p>Bug fixes</p>"},
+	}
+
+	merged := Releases(releases)
+	if len(merged) != 1 {
+		t.Fatalf("Expected one release, got %d: %+v", len(merged), merged)
+	}
+	if !strings.Contains(merged[0].Description, "terminal panel") {
+		t.Errorf("Expected the feed announcement as description, got %q", merged[0].Description)
+	}
+	if len(merged[0].Sources) != 2 || merged[0].Sources[0].Type != "feed-release" || merged[0].Sources[1].Type != "appstream" {
+		t.Errorf("Expected feed and appstream provenance, got %+v", merged[0].Sources)
+	}
+}
+
+func TestReleasesKeepsDistinctVersions(t *testing.T) {
+	releases := []models.Release{
+		{Version: "49.rc", Type: "gitlab-release"},
+		{Version: "49.0", Type: "gitlab-release"},
+		{Version: "", Type: "appstream", Title: "Untitled"},
+		{Version: "", Type: "appstream", Title: "Also untitled"},
+	}
+
+	if merged := Releases(releases); len(merged) != 4 {
+		t.Errorf("Expected no releases merged, got %d: %+v", len(merged), merged)
+	}
+}
+
+func TestReleasesIsIdempotent(t *testing.T) {
+	releases := []models.Release{
+		{Version: "1.0", Type: "gitea-release"},
+		{Version: "v1.0.0", Type: "gitea-tag"},
+		{Version: "1.0", Type: "appstream"},
+	}
+
+	once := Releases(releases)
+	twice := Releases(append(once, models.Release{Version: "1.0", Type: "feed-release"}))
+	if len(twice) != 1 || len(twice[0].Sources) != 4 {
+		t.Errorf("Expected one release with 4 sources, got %+v", twice)
+	}
+}
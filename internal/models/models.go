@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -25,60 +26,95 @@ type Metadata struct {
 
 // Stats contains aggregate statistics
 type Stats struct {
-	AppsTotal          int `json:"appsTotal"`
-	AppsWithGitHubRepo int `json:"appsWithGitHubRepo"`
-	AppsWithGitLabRepo int `json:"appsWithGitLabRepo"`
-	AppsWithChangelogs int `json:"appsWithChangelogs"`
-	TotalReleases      int `json:"totalReleases"`
+	AppsTotal             int `json:"appsTotal"`
+	AppsWithGitHubRepo    int `json:"appsWithGitHubRepo"`
+	AppsWithGitLabRepo    int `json:"appsWithGitLabRepo"`
+	AppsWithGiteaRepo     int `json:"appsWithGiteaRepo"`
+	AppsWithChangelogs    int `json:"appsWithChangelogs"`
+	TotalReleases         int `json:"totalReleases"`
+	HomebrewOutdated      int `json:"homebrewOutdated"`
+	HomebrewNoArm64Bottle int `json:"homebrewNoArm64Bottle"`
+	ReleasesWithoutAssets int `json:"releasesWithoutAssets"` // GitHub/GitLab/Gitea releases with no uploaded files
+	AppsVulnerable        int `json:"appsVulnerable"`        // Apps whose shipped version has known vulnerabilities (OSV)
 }
 
 // Performance contains timing breakdown
 type Performance struct {
-	FlathubFetchDuration string `json:"flathubFetchDuration"`
-	DetailsFetchDuration string `json:"detailsFetchDuration"`
-	GitHubFetchDuration  string `json:"githubFetchDuration"`
-	GitLabFetchDuration  string `json:"gitlabFetchDuration"`
-	MozillaFetchDuration string `json:"mozillaFetchDuration"`
-	OutputDuration       string `json:"outputDuration"`
+	FlathubFetchDuration   string `json:"flathubFetchDuration"`
+	DetailsFetchDuration   string `json:"detailsFetchDuration"`
+	GitHubFetchDuration    string `json:"githubFetchDuration"`
+	GitLabFetchDuration    string `json:"gitlabFetchDuration"`
+	GiteaFetchDuration     string `json:"giteaFetchDuration"`
+	GitTagsFetchDuration   string `json:"gitTagsFetchDuration"`
+	FeedFetchDuration      string `json:"feedFetchDuration"`
+	MozillaFetchDuration   string `json:"mozillaFetchDuration"`
+	NotesPageFetchDuration string `json:"notesPageFetchDuration"`
+	ChangelogFetchDuration string `json:"changelogFetchDuration"`
+	OSVCheckDuration       string `json:"osvCheckDuration"`
+	OutputDuration         string `json:"outputDuration"`
 }
 
 // App represents a Flathub application (similar to Release in firehose)
 type App struct {
-	ID                string        `json:"id"`
-	Name              string        `json:"name"`
-	Summary           string        `json:"summary"`
-	Description       string        `json:"description,omitempty"`
-	DeveloperName     string        `json:"developerName,omitempty"`
-	Icon              string        `json:"icon,omitempty"`
-	ProjectLicense    string        `json:"projectLicense,omitempty"`
-	Categories        []string      `json:"categories,omitempty"`
-	UpdatedAt         string        `json:"updatedAt,omitempty"`
-	Version           string        `json:"currentReleaseVersion,omitempty"`
-	ReleaseDate       string        `json:"currentReleaseDate,omitempty"`
-	FlathubURL        string        `json:"flathubUrl"`
-	SourceRepo        *SourceRepo   `json:"sourceRepo,omitempty"`
-	Releases          []Release     `json:"releases,omitempty"`
-	FetchedAt         time.Time     `json:"fetchedAt"`
-	InstallsLastMonth int           `json:"installsLastMonth,omitempty"`
-	FavoritesCount    int           `json:"favoritesCount,omitempty"`
-	IsVerified        bool          `json:"isVerified"`
-	VerificationInfo  *Verification `json:"verificationInfo,omitempty"`
-	AppSet            string        `json:"appSet,omitempty"` // "core" or "dx"
-	PackageType       string        `json:"packageType"`      // "flatpak", "homebrew", or "os"
-	HomebrewInfo      *HomebrewInfo `json:"homebrewInfo,omitempty"`
-	OSInfo            *OSInfo       `json:"osInfo,omitempty"`       // OS release-specific info
-	Experimental      bool          `json:"experimental,omitempty"` // Marks packages from experimental-tap as unstable
+	ID                string          `json:"id"`
+	Name              string          `json:"name"`
+	Summary           string          `json:"summary"`
+	Description       string          `json:"description,omitempty"`
+	DeveloperName     string          `json:"developerName,omitempty"`
+	Icon              string          `json:"icon,omitempty"`
+	ProjectLicense    string          `json:"projectLicense,omitempty"`
+	Categories        []string        `json:"categories,omitempty"`
+	UpdatedAt         string          `json:"updatedAt,omitempty"`
+	Version           string          `json:"currentReleaseVersion,omitempty"`
+	ReleaseDate       string          `json:"currentReleaseDate,omitempty"`
+	FlathubURL        string          `json:"flathubUrl"`
+	SourceRepo        *SourceRepo     `json:"sourceRepo,omitempty"`
+	Feed              *FeedSource     `json:"feed,omitempty"` // Release announcement feed (from source-overrides.json)
+	Releases          []Release       `json:"releases,omitempty"`
+	FetchedAt         time.Time       `json:"fetchedAt"`
+	InstallsLastMonth int             `json:"installsLastMonth,omitempty"`
+	FavoritesCount    int             `json:"favoritesCount,omitempty"`
+	IsVerified        bool            `json:"isVerified"`
+	VerificationInfo  *Verification   `json:"verificationInfo,omitempty"`
+	AppSet            string          `json:"appSet,omitempty"`   // "core" or "dx"
+	Brewfile          string          `json:"brewfile,omitempty"` // Homebrew Brewfile group (e.g., "cli", "fonts")
+	PackageType       string          `json:"packageType"`        // "flatpak", "homebrew", or "os"
+	HomebrewInfo      *HomebrewInfo   `json:"homebrewInfo,omitempty"`
+	UpstreamStatus    *UpstreamStatus `json:"upstreamStatus,omitempty"`  // Homebrew vs upstream version comparison
+	OSInfo            *OSInfo         `json:"osInfo,omitempty"`          // OS release-specific info
+	Experimental      bool            `json:"experimental,omitempty"`    // Marks packages from experimental-tap as unstable
+	Vulnerabilities   []Vulnerability `json:"vulnerabilities,omitempty"` // Known vulnerabilities of the shipped version (OSV)
 }
 
 // HomebrewInfo contains Homebrew-specific package information
 type HomebrewInfo struct {
-	Formula      string   `json:"formula"`                // Formula name (e.g., "bat", "gh")
-	FullName     string   `json:"fullName,omitempty"`     // Full formula name with tap (e.g., "homebrew/core/bat")
-	Tap          string   `json:"tap,omitempty"`          // Tap name (e.g., "homebrew/core")
-	Homepage     string   `json:"homepage,omitempty"`     // Homepage URL
-	Versions     []string `json:"versions,omitempty"`     // Available versions
-	Dependencies []string `json:"dependencies,omitempty"` // Package dependencies
-	Caveats      string   `json:"caveats,omitempty"`      // Installation caveats/notes
+	Formula      string      `json:"formula"`                // Formula name (e.g., "bat", "gh")
+	FullName     string      `json:"fullName,omitempty"`     // Full formula name with tap (e.g., "homebrew/core/bat")
+	Tap          string      `json:"tap,omitempty"`          // Tap name (e.g., "homebrew/core")
+	Homepage     string      `json:"homepage,omitempty"`     // Homepage URL
+	Versions     []string    `json:"versions,omitempty"`     // Available versions
+	Dependencies []string    `json:"dependencies,omitempty"` // Package dependencies
+	Caveats      string      `json:"caveats,omitempty"`      // Installation caveats/notes
+	Bottles      *BottleInfo `json:"bottles,omitempty"`      // Bottle availability (homebrew-core formulae only)
+}
+
+// BottleInfo describes which prebuilt Homebrew bottles exist for a formula
+type BottleInfo struct {
+	Tags             []string `json:"tags,omitempty"`             // All bottle tags (e.g., "arm64_linux", "x86_64_linux", "arm64_sonoma")
+	Rebuild          int      `json:"rebuild"`                    // Bottle rebuild number (0 for the first build of a version)
+	X86Linux         bool     `json:"x86_64Linux"`                // Native bottle for x86_64 Linux
+	Arm64Linux       bool     `json:"arm64Linux"`                 // Native bottle for aarch64 Linux
+	BuildsFromSource []string `json:"buildsFromSource,omitempty"` // Linux architectures that build from source ("x86_64", "aarch64")
+}
+
+// UpstreamStatus compares a packaged (Homebrew stable) version against the latest upstream release
+type UpstreamStatus struct {
+	PackagedVersion string `json:"packagedVersion"`        // e.g., "0.24.0"
+	UpstreamVersion string `json:"upstreamVersion"`        // Latest upstream stable tag, e.g., "v0.25.0"
+	UpstreamDate    string `json:"upstreamDate,omitempty"` // RFC3339 publish date of the latest upstream release
+	VersionsBehind  int    `json:"versionsBehind"`         // Upstream releases newer than the packaged version
+	DaysBehind      int    `json:"daysBehind"`             // Days since the first newer upstream release was published
+	Outdated        bool   `json:"outdated"`               // True when the lag exceeds the configured thresholds
 }
 
 // OSInfo contains Bluefin OS release-specific information
@@ -105,20 +141,152 @@ type Verification struct {
 
 // SourceRepo contains information about the app's source repository
 type SourceRepo struct {
-	Type  string `json:"type"` // "github", "gitlab", "other"
+	Type  string `json:"type"` // "github", "gitlab", "gitea", "git", "other"
 	URL   string `json:"url"`
 	Owner string `json:"owner,omitempty"`
 	Repo  string `json:"repo,omitempty"`
 }
 
+// FeedSource is an Atom/RSS feed announcing an app's releases (project blog, KDE announcements, Discourse category)
+type FeedSource struct {
+	URL    string `json:"url"`
+	Filter string `json:"filter,omitempty"` // Regular expression item titles must match (for feeds covering many projects)
+}
+
 // Release represents a single release/changelog entry (from GitHub, GitLab, or Flathub)
 type Release struct {
-	Version     string    `json:"version"`
-	Date        time.Time `json:"date"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	URL         string    `json:"url,omitempty"`
-	Type        string    `json:"type"` // "github-release", "gitlab-release", "appstream"
+	Version     string             `json:"version"`
+	Date        time.Time          `json:"date"`
+	Title       string             `json:"title"`
+	Description string             `json:"description,omitempty"`
+	PlainText   string             `json:"plainText,omitempty"` // Description as plain text
+	Excerpt     string             `json:"excerpt,omitempty"`   // Short plain-text summary of the description (first paragraph or bullets)
+	URL         string             `json:"url,omitempty"`
+	Type        string             `json:"type"`                 // "github-release", "gitlab-release", "gitea-release", "github-tag", "gitlab-tag", "gitea-tag", "git-tag", "feed-release", "mozilla-release", "mozilla-esr", "mozilla-beta", "appstream", "tap-update"
+	Assets      []ReleaseAsset     `json:"assets,omitempty"`     // Uploaded release files (GitHub/GitLab/Gitea releases only)
+	Prerelease  bool               `json:"prerelease,omitempty"` // Beta, RC or development release
+	Sources     []ReleaseSource    `json:"sources,omitempty"`    // Every source that reported this version (set by the merge step)
+	Security    bool               `json:"security,omitempty"`   // Fixes security vulnerabilities
+	Advisories  []SecurityAdvisory `json:"advisories,omitempty"` // Security advisories fixed by this release (Mozilla MFSA, OSV)
+	Notes       []ReleaseNote      `json:"notes,omitempty"`      // Structured notes parsed from a release notes page
+	Tags        []string           `json:"tags,omitempty"`       // Classification: "security", "breaking", "feature", "bugfix-only", "translation-only"
+	Severity    int                `json:"severity,omitempty"`   // 0 (routine) to 10 (critical security fix), from the tags and advisory impact
+}
+
+// ReleaseNote is one item of a release notes page (e.g., a "Fixed" entry in Firefox's notes)
+type ReleaseNote struct {
+	Category string   `json:"category"` // Section slug: "new", "fixed", "changed", "enterprise", "developer", "web-platform", "security", "known-issues", "unresolved"
+	Text     string   `json:"text"`     // Plain text of the note
+	Links    []string `json:"links,omitempty"`
+	Bugs     []int    `json:"bugs,omitempty"` // Bug tracker numbers referenced by the note
+}
+
+// SecurityAdvisory is a vendor security advisory fixed by a release (e.g., "MFSA 2024-29")
+type SecurityAdvisory struct {
+	ID     string        `json:"id"`
+	Title  string        `json:"title"`
+	URL    string        `json:"url"`
+	Impact string        `json:"impact"` // Highest severity: "critical", "high", "moderate" or "low"
+	CVEs   []AdvisoryCVE `json:"cves,omitempty"`
+}
+
+// AdvisoryCVE is one vulnerability covered by a security advisory
+type AdvisoryCVE struct {
+	ID     string `json:"id"` // "CVE-2024-6604"
+	Title  string `json:"title"`
+	Impact string `json:"impact"`
+}
+
+// Vulnerability is a known vulnerability (OSV record) affecting the version an app ships
+type Vulnerability struct {
+	ID       string   `json:"id"`                // OSV ID ("GHSA-xxxx-xxxx-xxxx", "GO-2024-1234")
+	Aliases  []string `json:"aliases,omitempty"` // Other IDs of the same vulnerability ("CVE-2024-1234")
+	Summary  string   `json:"summary,omitempty"`
+	URL      string   `json:"url"`                // osv.dev page
+	Severity string   `json:"severity,omitempty"` // "critical", "high", "moderate" or "low" when rated
+	Version  string   `json:"version"`            // Shipped version found vulnerable
+	Fixed    string   `json:"fixed,omitempty"`    // First upstream version with the fix, when known
+	FixedBy  string   `json:"fixedBy,omitempty"`  // Tracked release that fixes it (the release also lists the advisory)
+}
+
+// ReleaseSource records one source that reported a release, kept when releases from
+// several sources are merged into one
+type ReleaseSource struct {
+	Type string    `json:"type"` // Release type of the source ("github-release", "appstream", ...)
+	URL  string    `json:"url,omitempty"`
+	Date time.Time `json:"date"`
+}
+
+// Source returns the source a release came from, derived from its type
+// ("github-release" and "github-tag" -> "github", "bluefin-os-release" -> "bluefin-os", "tap-update" -> "tap",
+// "mozilla-esr" and "mozilla-beta" -> "mozilla")
+func (r Release) Source() string {
+	for _, suffix := range []string{"-release", "-tag", "-update", "-esr", "-beta"} {
+		if strings.HasSuffix(r.Type, suffix) {
+			return strings.TrimSuffix(r.Type, suffix)
+		}
+	}
+	return r.Type
+}
+
+// ReleaseAsset is a downloadable file attached to a GitHub, GitLab or Gitea release
+type ReleaseAsset struct {
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	Size          int64  `json:"size,omitempty"`          // Bytes (GitHub and Gitea)
+	ContentType   string `json:"contentType,omitempty"`   // MIME type (GitHub only)
+	DownloadCount int    `json:"downloadCount,omitempty"` // GitHub and Gitea; GitLab doesn't expose counts
+	ChecksumURL   string `json:"checksumUrl,omitempty"`   // Sidecar checksum (e.g., "app.tar.gz.sha256") or release-wide SHA256SUMS
+}
+
+// checksumSuffixes identify per-file checksum sidecars ("app.tar.gz.sha256")
+var checksumSuffixes = []string{".sha256", ".sha256sum", ".sha512", ".sha512sum", ".md5"}
+
+// checksumFiles identify release-wide checksum manifests (matched case-insensitively by substring)
+var checksumFiles = []string{"sha256sums", "sha512sums", "checksums", "sha256sum.txt"}
+
+// LinkAssetChecksums sets ChecksumURL on every non-checksum asset, preferring a per-file
+// sidecar over a release-wide manifest
+func LinkAssetChecksums(assets []ReleaseAsset) {
+	sidecars := make(map[string]string)
+	manifest := ""
+
+	for _, asset := range assets {
+		lower := strings.ToLower(asset.Name)
+		for _, suffix := range checksumSuffixes {
+			if strings.HasSuffix(lower, suffix) {
+				sidecars[strings.TrimSuffix(lower, suffix)] = asset.URL
+			}
+		}
+		for _, name := range checksumFiles {
+			if manifest == "" && strings.Contains(lower, name) {
+				manifest = asset.URL
+			}
+		}
+	}
+
+	for i := range assets {
+		asset := &assets[i]
+		lower := strings.ToLower(asset.Name)
+		if asset.URL == manifest || isChecksumName(lower) {
+			continue
+		}
+		if url, ok := sidecars[lower]; ok {
+			asset.ChecksumURL = url
+		} else {
+			asset.ChecksumURL = manifest
+		}
+	}
+}
+
+// isChecksumName reports whether a lowercased asset name is a checksum sidecar
+func isChecksumName(name string) bool {
+	for _, suffix := range checksumSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
 }
 
 // FlathubApp represents the raw structure from Flathub API collection endpoint
@@ -186,6 +354,7 @@ type FlathubAppDetails struct {
 // FlathubReleaseEntry represents a release from Flathub appstream metadata
 type FlathubReleaseEntry struct {
 	Version     string `json:"version"`
+	Type        string `json:"type"` // "stable" (default) or "development"
 	Date        string `json:"date"`
 	Timestamp   string `json:"timestamp"`
 	Description string `json:"description"`
@@ -0,0 +1,23 @@
+package models
+
+import "testing"
+
+func TestReleaseSource(t *testing.T) {
+	tests := map[string]string{
+		"github-release":     "github",
+		"github-tag":         "github",
+		"gitlab-release":     "gitlab",
+		"appstream":          "appstream",
+		"tap-update":         "tap",
+		"mozilla-release":    "mozilla",
+		"mozilla-esr":        "mozilla",
+		"mozilla-beta":       "mozilla",
+		"bluefin-os-release": "bluefin-os",
+	}
+
+	for releaseType, want := range tests {
+		if got := (Release{Type: releaseType}).Source(); got != want {
+			t.Errorf("Release{Type: %q}.Source() = %q, want %q", releaseType, got, want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package mozilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// AdvisoriesAPIURL lists the Mozilla Foundation Security Advisories by year
+// (announce/2024/mfsa2024-29.yml in mozilla/foundation-security-advisories)
+var AdvisoriesAPIURL = "https://api.github.com/repos/mozilla/foundation-security-advisories/contents/announce"
+
+// advisoryConcurrency limits parallel advisory downloads
+const advisoryConcurrency = 10
+
+// advisoryFileRe matches advisory file names ("mfsa2024-29.yml")
+var advisoryFileRe = regexp.MustCompile(`^mfsa(\d{4})-(\d+)\.yml$`)
+
+// fixedInRe parses an advisory's fixed_in entries ("Firefox 128", "Firefox ESR 115.13", "Thunderbird 128.0.1")
+var fixedInRe = regexp.MustCompile(`^(Firefox|Thunderbird)(?: (ESR))? (\d+(?:\.\d+)*)$`)
+
+// advisoryListing is an entry of the GitHub contents API listing
+type advisoryListing struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DownloadURL string `json:"download_url"`
+}
+
+// advisoryFile is an MFSA YAML file
+type advisoryFile struct {
+	Announced  string                   `yaml:"announced"`
+	Impact     string                   `yaml:"impact"`
+	FixedIn    []string                 `yaml:"fixed_in"`
+	Title      string                   `yaml:"title"`
+	Advisories map[string]advisoryEntry `yaml:"advisories"`
+}
+
+// advisoryEntry is one vulnerability in an MFSA file, keyed by CVE ID
+type advisoryEntry struct {
+	Title  string `yaml:"title"`
+	Impact string `yaml:"impact"`
+}
+
+// advisoryIndex maps a fixed-in key ("firefox 128", "firefox 115.13esr") to the advisories fixed there
+type advisoryIndex map[string][]models.SecurityAdvisory
+
+// attachAdvisories marks releases that fix security advisories and records the advisories
+func attachAdvisories(releases []models.Release, p product, index advisoryIndex) {
+	for i := range releases {
+		release := &releases[i]
+		v, ok := version.Parse(release.Version)
+		if !ok {
+			continue
+		}
+
+		advisories := index[p.Key+" "+v.Normalized()]
+		if len(advisories) == 0 && v.ESR {
+			// Thunderbird advisories list ESR builds without the ESR label
+			v.ESR = false
+			advisories = index[p.Key+" "+v.Normalized()]
+		}
+		if len(advisories) == 0 {
+			continue
+		}
+
+		release.Advisories = advisories
+		release.Security = true
+	}
+}
+
+// fetchAdvisories downloads the advisories announced in the given years and indexes them by
+// the product versions that fix them
+func fetchAdvisories(client *http.Client, years []int) (advisoryIndex, error) {
+	var files []advisoryListing
+	for _, year := range years {
+		listing, err := listAdvisories(client, year)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, listing...)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		sem   = make(chan struct{}, advisoryConcurrency)
+		index = make(advisoryIndex)
+	)
+
+	for _, file := range files {
+		wg.Add(1)
+		go func(file advisoryListing) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			advisory, fixedIn, err := fetchAdvisory(client, file)
+			if err != nil {
+				log.Printf("⚠️  Failed to read advisory %s: %v", file.Name, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, key := range fixedIn {
+				index[key] = append(index[key], advisory)
+			}
+		}(file)
+	}
+	wg.Wait()
+
+	// Downloads finish in any order; keep advisories sorted by ID
+	for key := range index {
+		sort.Slice(index[key], func(i, j int) bool {
+			return index[key][i].ID < index[key][j].ID
+		})
+	}
+
+	return index, nil
+}
+
+// listAdvisories lists the MFSA YAML files of one year
+func listAdvisories(client *http.Client, year int) ([]advisoryListing, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%d", AdvisoriesAPIURL, year), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("list advisories: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// A new year has no directory until its first advisory
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("advisory listing returned status %d", resp.StatusCode)
+	}
+
+	var listing []advisoryListing
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("decode advisory listing: %w", err)
+	}
+
+	files := listing[:0]
+	for _, entry := range listing {
+		if entry.Type == "file" && advisoryFileRe.MatchString(entry.Name) {
+			files = append(files, entry)
+		}
+	}
+	return files, nil
+}
+
+// fetchAdvisory downloads and parses one MFSA file, returning the advisory and its fixed-in keys
+func fetchAdvisory(client *http.Client, file advisoryListing) (models.SecurityAdvisory, []string, error) {
+	resp, err := client.Get(file.DownloadURL)
+	if err != nil {
+		return models.SecurityAdvisory{}, nil, fmt.Errorf("fetch advisory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return models.SecurityAdvisory{}, nil, fmt.Errorf("advisory returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return models.SecurityAdvisory{}, nil, fmt.Errorf("read advisory: %w", err)
+	}
+
+	return parseAdvisory(file.Name, data)
+}
+
+// parseAdvisory parses an MFSA YAML file named like "mfsa2024-29.yml"
+func parseAdvisory(name string, data []byte) (models.SecurityAdvisory, []string, error) {
+	match := advisoryFileRe.FindStringSubmatch(name)
+	if match == nil {
+		return models.SecurityAdvisory{}, nil, fmt.Errorf("unexpected advisory file name %q", name)
+	}
+
+	var file advisoryFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return models.SecurityAdvisory{}, nil, fmt.Errorf("parse advisory: %w", err)
+	}
+
+	slug := strings.TrimSuffix(name, ".yml")
+	advisory := models.SecurityAdvisory{
+		ID:     fmt.Sprintf("MFSA %s-%s", match[1], match[2]),
+		Title:  file.Title,
+		URL:    fmt.Sprintf("https://www.mozilla.org/en-US/security/advisories/%s/", slug),
+		Impact: strings.ToLower(file.Impact),
+	}
+
+	for id, entry := range file.Advisories {
+		if !strings.HasPrefix(id, "CVE-") {
+			continue
+		}
+		advisory.CVEs = append(advisory.CVEs, models.AdvisoryCVE{
+			ID:     id,
+			Title:  strings.TrimSpace(entry.Title),
+			Impact: strings.ToLower(entry.Impact),
+		})
+	}
+	sort.Slice(advisory.CVEs, func(i, j int) bool {
+		return advisory.CVEs[i].ID < advisory.CVEs[j].ID
+	})
+
+	var fixedIn []string
+	for _, entry := range file.FixedIn {
+		if key := fixedInKey(strings.TrimSpace(entry)); key != "" {
+			fixedIn = append(fixedIn, key)
+		}
+	}
+
+	return advisory, fixedIn, nil
+}
+
+// fixedInKey converts a fixed_in entry to an index key ("Firefox ESR 115.13" -> "firefox 115.13esr")
+func fixedInKey(entry string) string {
+	match := fixedInRe.FindStringSubmatch(entry)
+	if match == nil {
+		return ""
+	}
+
+	v, ok := version.Parse(match[3])
+	if !ok {
+		return ""
+	}
+	v.ESR = match[2] != ""
+	return strings.ToLower(match[1]) + " " + v.Normalized()
+}
@@ -0,0 +1,143 @@
+package mozilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+const mfsa202429 = `## mfsa2024-29.yml
+announced: July 9, 2024
+impact: high
+fixed_in:
+- Firefox 128
+title: Security Vulnerabilities fixed in Firefox 128
+description: |
+  Fixes several memory safety issues.
+advisories:
+  CVE-2024-6605:
+    title: Firefox Android missed activation delay to prevent tapjacking
+    impact: moderate
+    reporter: Dominik Hruby
+    description: |
+      Firefox Android missed activation delay.
+    bugs:
+    - url: 1836786
+  CVE-2024-6604:
+    title: Memory safety bugs fixed in Firefox 128, Firefox ESR 115.13, Thunderbird 115.13, and Thunderbird 128
+    impact: high
+    reporter: Mozilla developers
+    description: |
+      Memory safety bugs present in Firefox 127.
+    bugs:
+    - url: 1748105, 1837550
+  MFSA-TMP-2024-0001:
+    title: Placeholder entry
+    impact: low
+`
+
+const mfsa202430 = `announced: July 9, 2024
+impact: high
+fixed_in:
+- Firefox ESR 115.13
+- Thunderbird 115.13
+title: Security Vulnerabilities fixed in Firefox ESR 115.13
+advisories:
+  CVE-2024-6604:
+    title: Memory safety bugs fixed in Firefox ESR 115.13
+    impact: high
+`
+
+func TestParseAdvisory(t *testing.T) {
+	advisory, fixedIn, err := parseAdvisory("mfsa2024-29.yml", []byte(mfsa202429))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if advisory.ID != "MFSA 2024-29" || advisory.Impact != "high" ||
+		advisory.URL != "https://www.mozilla.org/en-US/security/advisories/mfsa2024-29/" {
+		t.Errorf("Unexpected advisory: %+v", advisory)
+	}
+	if len(advisory.CVEs) != 2 || advisory.CVEs[0].ID != "CVE-2024-6604" || advisory.CVEs[1].Impact != "moderate" {
+		t.Errorf("Unexpected CVEs: %+v", advisory.CVEs)
+	}
+	if len(fixedIn) != 1 || fixedIn[0] != "firefox 128" {
+		t.Errorf("Unexpected fixed-in keys: %v", fixedIn)
+	}
+}
+
+func TestFixedInKey(t *testing.T) {
+	tests := map[string]string{
+		"Firefox 128":          "firefox 128",
+		"Firefox 127.0.2":      "firefox 127.0.2",
+		"Firefox ESR 115.13":   "firefox 115.13esr",
+		"Thunderbird 128.0.1":  "thunderbird 128.0.1",
+		"Firefox for iOS 128":  "",
+		"Focus for Android 12": "",
+	}
+	for entry, want := range tests {
+		if got := fixedInKey(entry); got != want {
+			t.Errorf("fixedInKey(%q) = %q, want %q", entry, got, want)
+		}
+	}
+}
+
+func TestFetchAndAttachAdvisories(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/announce/2024":
+			fmt.Fprintf(w, `[
+  {"name": "mfsa2024-29.yml", "type": "file", "download_url": "%[1]s/raw/mfsa2024-29.yml"},
+  {"name": "mfsa2024-30.yml", "type": "file", "download_url": "%[1]s/raw/mfsa2024-30.yml"},
+  {"name": "README.md", "type": "file", "download_url": "%[1]s/raw/README.md"}
+]`, server.URL)
+		case "/raw/mfsa2024-29.yml":
+			fmt.Fprint(w, mfsa202429)
+		case "/raw/mfsa2024-30.yml":
+			fmt.Fprint(w, mfsa202430)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	original := AdvisoriesAPIURL
+	AdvisoriesAPIURL = server.URL + "/announce"
+	defer func() { AdvisoriesAPIURL = original }()
+
+	july := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+	firefox := []models.Release{
+		{Version: "128.0", Type: "mozilla-release", Date: july},
+		{Version: "127.0.2", Type: "mozilla-release", Date: july.AddDate(0, 0, -14)},
+		{Version: "115.13.0esr", Type: "mozilla-esr", Date: july},
+	}
+	thunderbird := []models.Release{
+		{Version: "115.13.0esr", Type: "mozilla-esr", Date: july},
+	}
+
+	index, err := fetchAdvisories(server.Client(), advisoryYears(append(firefox, thunderbird...)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	attachAdvisories(firefox, products[0], index)
+	attachAdvisories(thunderbird, products[1], index)
+
+	if !firefox[0].Security || len(firefox[0].Advisories) != 1 || firefox[0].Advisories[0].ID != "MFSA 2024-29" {
+		t.Errorf("Expected Firefox 128 to carry MFSA 2024-29, got %+v", firefox[0])
+	}
+	if firefox[1].Security || len(firefox[1].Advisories) != 0 {
+		t.Errorf("Firefox 127.0.2 has no advisory, got %+v", firefox[1])
+	}
+	if !firefox[2].Security || firefox[2].Advisories[0].ID != "MFSA 2024-30" {
+		t.Errorf("Expected Firefox ESR 115.13 to carry MFSA 2024-30, got %+v", firefox[2])
+	}
+	if !thunderbird[0].Security || thunderbird[0].Advisories[0].ID != "MFSA 2024-30" {
+		t.Errorf("Expected Thunderbird 115.13 ESR to match the unlabeled fixed-in entry, got %+v", thunderbird[0])
+	}
+}
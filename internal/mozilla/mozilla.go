@@ -1,325 +1,325 @@
+// Package mozilla builds Firefox and Thunderbird release history from Mozilla's
+// product-details service, with release notes from the mozilla.org / thunderbird.net pages.
 package mozilla
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/notespage"
+	"github.com/castrojo/bluefin-releases/internal/version"
 )
 
-// EnrichWithMozillaReleases fetches release notes for Firefox and Thunderbird
+// ProductDetailsURL is the base URL of the product-details service
+var ProductDetailsURL = "https://product-details.mozilla.org/1.0"
+
+// Releases kept per channel: recent releases, the latest release of each supported ESR
+// branch, and the current beta
+const (
+	ReleasesPerProduct = 5
+	ESRBranches        = 2
+	Betas              = 1
+)
+
+// product describes a Mozilla product tracked on Flathub
+type product struct {
+	AppID    string
+	Name     string // Display name ("Firefox")
+	Key      string // product-details file name ("firefox" for firefox.json)
+	NotesURL string // Release notes page with %s for the version ("128.0", "129.0beta")
+}
+
+var products = []product{
+	{
+		AppID:    "org.mozilla.firefox",
+		Name:     "Firefox",
+		Key:      "firefox",
+		NotesURL: "https://www.mozilla.org/en-US/firefox/%s/releasenotes/",
+	},
+	{
+		AppID:    "org.mozilla.Thunderbird",
+		Name:     "Thunderbird",
+		Key:      "thunderbird",
+		NotesURL: "https://www.thunderbird.net/en-US/thunderbird/%s/releasenotes/",
+	},
+}
+
+// productHistory is a product-details history file (firefox.json, thunderbird.json)
+type productHistory struct {
+	Releases map[string]productRelease `json:"releases"`
+}
+
+// productRelease is one entry of a product-details history file, keyed by "firefox-128.0"
+type productRelease struct {
+	BuildNumber      int    `json:"build_number"`
+	Category         string `json:"category"` // "major", "stability", "esr" or "dev"
+	Date             string `json:"date"`     // YYYY-MM-DD
+	Description      string `json:"description"`
+	IsSecurityDriven bool   `json:"is_security_driven"`
+	Product          string `json:"product"`
+	Version          string `json:"version"`
+}
+
+// Release channels and the release type each maps to
+const (
+	channelRelease = "mozilla-release"
+	channelESR     = "mozilla-esr"
+	channelBeta    = "mozilla-beta"
+)
+
+// EnrichWithMozillaReleases replaces the Flathub releases of Firefox and Thunderbird with
+// their release, ESR and beta history, flagging releases that fix security advisories
 func EnrichWithMozillaReleases(apps []models.App) []models.App {
 	log.Println("Enriching Mozilla products with release notes...")
+	client := &http.Client{Timeout: 15 * time.Second}
 
 	enrichedApps := make([]models.App, len(apps))
 	copy(enrichedApps, apps)
 
+	type productApp struct {
+		app     *models.App
+		product product
+	}
+	var found []productApp
+
 	for i := range enrichedApps {
 		app := &enrichedApps[i]
 
-		// Check if this is Firefox or Thunderbird
-		if app.ID == "org.mozilla.firefox" {
-			if releases, err := fetchFirefoxReleases(); err == nil {
-				// Replace the single Flathub release with actual Firefox releases
-				app.Releases = releases
-				log.Printf("✅ Added %d Firefox releases", len(releases))
-			} else {
-				log.Printf("⚠️  Failed to fetch Firefox releases: %v", err)
+		for _, p := range products {
+			if app.ID != p.AppID {
+				continue
 			}
-		} else if app.ID == "org.mozilla.Thunderbird" {
-			if releases, err := fetchThunderbirdReleases(); err == nil {
-				// Replace the single Flathub release with actual Thunderbird releases
-				app.Releases = releases
-				log.Printf("✅ Added %d Thunderbird releases", len(releases))
-			} else {
-				log.Printf("⚠️  Failed to fetch Thunderbird releases: %v", err)
+
+			releases, err := fetchProductReleases(client, p)
+			if err != nil {
+				log.Printf("⚠️  Failed to fetch %s releases: %v", p.Name, err)
+				break
 			}
+			// Replace the single Flathub release with actual release history
+			app.Releases = releases
+			found = append(found, productApp{app: app, product: p})
+			log.Printf("✅ Added %d %s releases", len(releases), p.Name)
 		}
 	}
 
-	return enrichedApps
-}
-
-// fetchFirefoxReleases fetches the latest Firefox release notes
-func fetchFirefoxReleases() ([]models.Release, error) {
-	// First, get the latest version
-	resp, err := http.Get("https://product-details.mozilla.org/1.0/firefox_versions.json")
-	if err != nil {
-		return nil, fmt.Errorf("fetch version info: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read version info: %w", err)
+	if len(found) == 0 {
+		return enrichedApps
 	}
 
-	// Extract version (simple regex since we just need LATEST_FIREFOX_VERSION)
-	versionRe := regexp.MustCompile(`"LATEST_FIREFOX_VERSION":\s*"([^"]+)"`)
-	matches := versionRe.FindStringSubmatch(string(body))
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("could not find latest version")
+	// Advisories cover Firefox and Thunderbird together, so they are fetched once
+	var all []models.Release
+	for _, f := range found {
+		all = append(all, f.app.Releases...)
 	}
-
-	version := matches[1]
-
-	// Fetch the release notes page
-	releaseNotesURL := fmt.Sprintf("https://www.mozilla.org/en-US/firefox/%s/releasenotes/", version)
-	resp, err = http.Get(releaseNotesURL)
+	index, err := fetchAdvisories(client, advisoryYears(all))
 	if err != nil {
-		return nil, fmt.Errorf("fetch release notes: %w", err)
+		log.Printf("⚠️  Failed to fetch Mozilla security advisories: %v", err)
+		return enrichedApps
 	}
-	defer resp.Body.Close()
 
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read release notes: %w", err)
+	for _, f := range found {
+		attachAdvisories(f.app.Releases, f.product, index)
 	}
+	log.Printf("✅ Matched Mozilla security advisories (%d fixed-in versions indexed)", len(index))
 
-	html := string(body)
-
-	// Extract release notes content
-	description := extractFirefoxReleaseNotes(html)
-
-	// Parse release date from page if available
-	dateStr := extractReleaseDate(html)
-	releaseDate := time.Now()
-	if dateStr != "" {
-		// Try ISO 8601 format first (from datetime attribute)
-		formats := []string{"2006-01-02", time.RFC3339, "January 2, 2006"}
-		parsed := false
-		for _, fmt := range formats {
-			if parsedDate, err := time.Parse(fmt, dateStr); err == nil {
-				releaseDate = parsedDate
-				parsed = true
-				break
-			}
+	return enrichedApps
+}
+
+// advisoryYears returns the years spanned by the release dates, oldest first
+func advisoryYears(releases []models.Release) []int {
+	first, last := 0, 0
+	for _, release := range releases {
+		if release.Date.IsZero() {
+			continue
 		}
-		if !parsed {
-			log.Printf("⚠️  Could not parse Firefox release date: %s", dateStr)
+		year := release.Date.Year()
+		if first == 0 || year < first {
+			first = year
+		}
+		if year > last {
+			last = year
 		}
 	}
 
-	return []models.Release{
-		{
-			Version:     version,
-			Date:        releaseDate,
-			Title:       fmt.Sprintf("Firefox %s", version),
-			Description: description,
-			URL:         releaseNotesURL,
-			Type:        "mozilla-release",
-		},
-	}, nil
+	var years []int
+	for year := first; first != 0 && year <= last; year++ {
+		years = append(years, year)
+	}
+	return years
 }
 
-// fetchThunderbirdReleases fetches the latest Thunderbird release notes
-func fetchThunderbirdReleases() ([]models.Release, error) {
-	// Thunderbird uses a similar structure but different API
-	resp, err := http.Get("https://product-details.mozilla.org/1.0/thunderbird_versions.json")
+// fetchProductReleases reads a product's history from product-details and fills in
+// release notes for each selected release
+func fetchProductReleases(client *http.Client, p product) ([]models.Release, error) {
+	history, err := fetchHistory(client, fmt.Sprintf("%s/%s.json", ProductDetailsURL, p.Key))
 	if err != nil {
-		return nil, fmt.Errorf("fetch version info: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read version info: %w", err)
+	releases := selectReleases(history, p)
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no releases in %s history", p.Key)
 	}
 
-	// Extract version
-	versionRe := regexp.MustCompile(`"LATEST_THUNDERBIRD_VERSION":\s*"([^"]+)"`)
-	matches := versionRe.FindStringSubmatch(string(body))
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("could not find latest version")
+	for i := range releases {
+		release := &releases[i]
+		notes, err := fetchReleaseNotes(client, release.URL)
+		if err != nil {
+			log.Printf("⚠️  No release notes for %s: %v", release.Title, err)
+			continue
+		}
+		if len(notes.Notes) == 0 {
+			log.Printf("⚠️  No notes found on the %s release notes page", release.Title)
+			release.Description = "<p>Release notes available at the source link.</p>"
+			continue
+		}
+		release.Notes = notes.Structured()
+		release.Description = notes.HTML()
 	}
 
-	version := matches[1]
+	return releases, nil
+}
 
-	// Fetch the release notes page
-	releaseNotesURL := fmt.Sprintf("https://www.thunderbird.net/en-US/thunderbird/%s/releasenotes/", version)
-	resp, err = http.Get(releaseNotesURL)
+// fetchHistory downloads and decodes a product-details history file
+func fetchHistory(client *http.Client, historyURL string) (*productHistory, error) {
+	resp, err := client.Get(historyURL)
 	if err != nil {
-		return nil, fmt.Errorf("fetch release notes: %w", err)
+		return nil, fmt.Errorf("fetch release history: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read release notes: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release history returned status %d", resp.StatusCode)
 	}
 
-	html := string(body)
-
-	// Extract release notes content
-	description := extractThunderbirdReleaseNotes(html)
-
-	// Parse release date
-	dateStr := extractReleaseDate(html)
-	releaseDate := time.Now()
-	if dateStr != "" {
-		// Try ISO 8601 format first (from datetime attribute)
-		formats := []string{"2006-01-02", time.RFC3339, "January 2, 2006"}
-		parsed := false
-		for _, fmt := range formats {
-			if parsedDate, err := time.Parse(fmt, dateStr); err == nil {
-				releaseDate = parsedDate
-				parsed = true
-				break
-			}
-		}
-		if !parsed {
-			log.Printf("⚠️  Could not parse Thunderbird release date: %s", dateStr)
-		}
+	var history productHistory
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("decode release history: %w", err)
 	}
-
-	return []models.Release{
-		{
-			Version:     version,
-			Date:        releaseDate,
-			Title:       fmt.Sprintf("Thunderbird %s", version),
-			Description: description,
-			URL:         releaseNotesURL,
-			Type:        "mozilla-release",
-		},
-	}, nil
+	return &history, nil
 }
 
-// extractFirefoxReleaseNotes extracts and formats release notes from Firefox HTML
-func extractFirefoxReleaseNotes(html string) string {
-	var sections []string
-
-	// Extract "New" section
-	if newSection := extractSection(html, "New"); newSection != "" {
-		sections = append(sections, "## New\n\n"+newSection)
-	}
-
-	// Extract "Fixed" section
-	if fixedSection := extractSection(html, "Fixed"); fixedSection != "" {
-		sections = append(sections, "## Fixed\n\n"+fixedSection)
-	}
-
-	// Extract "Changed" section
-	if changedSection := extractSection(html, "Changed"); changedSection != "" {
-		sections = append(sections, "## Changed\n\n"+changedSection)
+// selectReleases picks the newest releases of each channel from a product history:
+// ReleasesPerProduct major/stability releases, the latest release of the newest ESRBranches
+// ESR branches, and the newest beta when it is ahead of the latest release.
+// Results are ordered by version, newest first.
+func selectReleases(history *productHistory, p product) []models.Release {
+	var stable, esr, beta []productRelease
+	for _, entry := range history.Releases {
+		switch entry.Category {
+		case "major", "stability":
+			stable = append(stable, entry)
+		case "esr":
+			esr = append(esr, entry)
+		case "dev":
+			beta = append(beta, entry)
+		}
 	}
+	sortByVersionDesc(stable)
+	sortByVersionDesc(esr)
+	sortByVersionDesc(beta)
 
-	// Extract "Enterprise" section
-	if enterpriseSection := extractSection(html, "Enterprise"); enterpriseSection != "" {
-		sections = append(sections, "## Enterprise\n\n"+enterpriseSection)
+	var releases []models.Release
+	for i := 0; i < len(stable) && i < ReleasesPerProduct; i++ {
+		releases = append(releases, convertRelease(stable[i], p, channelRelease))
 	}
 
-	// Extract "Developer" section
-	if devSection := extractSection(html, "Developer"); devSection != "" {
-		sections = append(sections, "## Developer\n\n"+devSection)
+	branches := make(map[int]bool)
+	for _, entry := range esr {
+		if len(branches) >= ESRBranches {
+			break
+		}
+		v, ok := version.Parse(entry.Version)
+		if !ok || branches[v.Numbers[0]] {
+			continue
+		}
+		branches[v.Numbers[0]] = true
+		releases = append(releases, convertRelease(entry, p, channelESR))
 	}
 
-	if len(sections) == 0 {
-		return "<p>Release notes available at the source link.</p>"
+	for i := 0; i < len(beta) && i < Betas; i++ {
+		if len(stable) > 0 && version.Compare(beta[i].Version, stable[0].Version) <= 0 {
+			break
+		}
+		releases = append(releases, convertRelease(beta[i], p, channelBeta))
 	}
 
-	markdownContent := strings.Join(sections, "\n\n")
-	return markdown.ToHTML(markdownContent)
-}
-
-// extractThunderbirdReleaseNotes extracts and formats release notes from Thunderbird HTML
-func extractThunderbirdReleaseNotes(html string) string {
-	// Thunderbird has similar structure to Firefox
-	return extractFirefoxReleaseNotes(html)
+	sort.SliceStable(releases, func(i, j int) bool {
+		return version.Compare(releases[i].Version, releases[j].Version) > 0
+	})
+	return releases
 }
 
-// extractSection extracts a specific section from the release notes HTML
-func extractSection(html, sectionName string) string {
-	// Mozilla's structure: <h3>SectionName</h3> ... <ul> ... </ul>
-	// The h3 and ul are in different divs, so we need to match more flexibly
-
-	// Find the section starting from the h3
-	sectionStartRe := regexp.MustCompile(fmt.Sprintf(`<h3>%s</h3>`, regexp.QuoteMeta(sectionName)))
-	startIdx := sectionStartRe.FindStringIndex(html)
-	if startIdx == nil {
-		return ""
+// convertRelease converts a product-details entry to a release of the given channel type
+func convertRelease(entry productRelease, p product, channel string) models.Release {
+	date, err := time.Parse("2006-01-02", entry.Date)
+	if err != nil {
+		log.Printf("⚠️  Could not parse %s %s release date: %s", p.Name, entry.Version, entry.Date)
 	}
 
-	// Find the next <ul> after this h3 (use (?s) to match across newlines)
-	remaining := html[startIdx[1]:]
-	ulRe := regexp.MustCompile(`(?s)<ul>(.*?)</ul>`)
-	ulMatches := ulRe.FindStringSubmatch(remaining)
-	if len(ulMatches) < 2 {
-		return ""
+	title := fmt.Sprintf("%s %s", p.Name, entry.Version)
+	switch channel {
+	case channelESR:
+		title = fmt.Sprintf("%s ESR %s", p.Name, strings.TrimSuffix(entry.Version, "esr"))
+	case channelBeta:
+		title = fmt.Sprintf("%s Beta %s", p.Name, entry.Version)
 	}
 
-	listHTML := ulMatches[1]
+	return models.Release{
+		Version:    entry.Version,
+		Date:       date,
+		Title:      title,
+		URL:        fmt.Sprintf(p.NotesURL, notesVersion(entry.Version, p, channel)),
+		Type:       channel,
+		Prerelease: channel == channelBeta,
+		Security:   entry.IsSecurityDriven,
+	}
+}
 
-	// Extract list items with release-note class (use (?s) for multiline matching)
-	itemRe := regexp.MustCompile(`(?s)<li[^>]*class="release-note"[^>]*>.*?<div class="release-note-content">(.*?)</div>`)
-	itemMatches := itemRe.FindAllStringSubmatch(listHTML, -1)
+// betaVersionRe matches the major.minor part of a beta version ("129.0b3" -> "129.0")
+var betaVersionRe = regexp.MustCompile(`^(\d+\.\d+)b\d+$`)
 
-	var items []string
-	for _, match := range itemMatches {
-		if len(match) > 1 {
-			content := match[1]
-			// Clean up HTML
-			content = cleanHTML(content)
-			if content != "" {
-				items = append(items, "- "+content)
-			}
+// notesVersion returns the version used in release notes URLs. Betas share one page per
+// major version ("129.0beta"); Firefox ESR notes drop the "esr" suffix while Thunderbird keeps it.
+func notesVersion(v string, p product, channel string) string {
+	switch channel {
+	case channelBeta:
+		if match := betaVersionRe.FindStringSubmatch(v); match != nil {
+			return match[1] + "beta"
+		}
+	case channelESR:
+		if p.Key == "firefox" {
+			return strings.TrimSuffix(v, "esr")
 		}
 	}
-
-	return strings.Join(items, "\n")
+	return v
 }
 
-// extractReleaseDate extracts the release date from the HTML
-func extractReleaseDate(html string) string {
-	// Try <time datetime="..."> first (old format)
-	dateRe := regexp.MustCompile(`<time[^>]*datetime="([^"]+)"`)
-	matches := dateRe.FindStringSubmatch(html)
-	if len(matches) > 1 {
-		return matches[1]
-	}
+// sortByVersionDesc sorts product-details entries by version, newest first
+func sortByVersionDesc(entries []productRelease) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return version.Compare(entries[i].Version, entries[j].Version) > 0
+	})
+}
 
-	// Try Firefox format: <p class="c-release-date">February 4, 2026</p>
-	firefoxRe := regexp.MustCompile(`<p class="c-release-date">([^<]+)</p>`)
-	matches = firefoxRe.FindStringSubmatch(html)
-	if len(matches) > 1 {
-		return strings.TrimSpace(matches[1])
+// fetchReleaseNotes downloads and parses a release notes page
+func fetchReleaseNotes(client *http.Client, notesURL string) (*notespage.Page, error) {
+	resp, err := client.Get(notesURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch release notes: %w", err)
 	}
+	defer resp.Body.Close()
 
-	// Try generic "Released DATE" pattern (Thunderbird and others)
-	releasedRe := regexp.MustCompile(`Released\s+((?:January|February|March|April|May|June|July|August|September|October|November|December)\s+\d+,\s+\d{4})`)
-	matches = releasedRe.FindStringSubmatch(html)
-	if len(matches) > 1 {
-		return strings.TrimSpace(matches[1])
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release notes returned status %d", resp.StatusCode)
 	}
 
-	return ""
-}
-
-// cleanHTML removes HTML tags and cleans up text
-func cleanHTML(html string) string {
-	// Remove line breaks
-	text := strings.ReplaceAll(html, "\n", " ")
-	text = strings.ReplaceAll(text, "\r", " ")
-
-	// Convert <code> tags to backticks
-	text = regexp.MustCompile(`<code[^>]*>`).ReplaceAllString(text, "`")
-	text = regexp.MustCompile(`</code>`).ReplaceAllString(text, "`")
-
-	// Convert links to markdown
-	linkRe := regexp.MustCompile(`<a[^>]*href="([^"]+)"[^>]*>(.*?)</a>`)
-	text = linkRe.ReplaceAllString(text, "[$2]($1)")
-
-	// Remove remaining HTML tags
-	text = regexp.MustCompile(`<[^>]+>`).ReplaceAllString(text, "")
-
-	// Clean up whitespace
-	text = regexp.MustCompile(`\s+`).ReplaceAllString(text, " ")
-	text = strings.TrimSpace(text)
-
-	return text
+	return ParseReleaseNotes(resp.Body, notesURL)
 }
@@ -0,0 +1,73 @@
+package mozilla
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const firefoxHistory = `{
+  "releases": {
+    "firefox-127.0": {"build_number": 2, "category": "major", "date": "2024-06-11", "description": "", "is_security_driven": false, "product": "firefox", "version": "127.0"},
+    "firefox-127.0.2": {"build_number": 1, "category": "stability", "date": "2024-06-25", "description": "", "is_security_driven": false, "product": "firefox", "version": "127.0.2"},
+    "firefox-128.0": {"build_number": 3, "category": "major", "date": "2024-07-09", "description": "", "is_security_driven": false, "product": "firefox", "version": "128.0"},
+    "firefox-128.0b3": {"build_number": 1, "category": "dev", "date": "2024-06-14", "description": "", "is_security_driven": false, "product": "firefox", "version": "128.0b3"},
+    "firefox-129.0b2": {"build_number": 1, "category": "dev", "date": "2024-07-12", "description": "", "is_security_driven": false, "product": "firefox", "version": "129.0b2"},
+    "firefox-115.12.0esr": {"build_number": 1, "category": "esr", "date": "2024-06-11", "description": "", "is_security_driven": false, "product": "firefox", "version": "115.12.0esr"},
+    "firefox-115.13.0esr": {"build_number": 1, "category": "esr", "date": "2024-07-09", "description": "", "is_security_driven": true, "product": "firefox", "version": "115.13.0esr"},
+    "firefox-128.0esr": {"build_number": 1, "category": "esr", "date": "2024-07-09", "description": "", "is_security_driven": false, "product": "firefox", "version": "128.0esr"},
+    "firefox-102.15.1esr": {"build_number": 1, "category": "esr", "date": "2023-09-12", "description": "", "is_security_driven": false, "product": "firefox", "version": "102.15.1esr"}
+  }
+}`
+
+func TestSelectReleases(t *testing.T) {
+	var history productHistory
+	if err := json.Unmarshal([]byte(firefoxHistory), &history); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	releases := selectReleases(&history, products[0])
+
+	want := []struct {
+		version, releaseType, url string
+	}{
+		{"129.0b2", "mozilla-beta", "https://www.mozilla.org/en-US/firefox/129.0beta/releasenotes/"},
+		{"128.0", "mozilla-release", "https://www.mozilla.org/en-US/firefox/128.0/releasenotes/"},
+		{"128.0esr", "mozilla-esr", "https://www.mozilla.org/en-US/firefox/128.0/releasenotes/"},
+		{"127.0.2", "mozilla-release", "https://www.mozilla.org/en-US/firefox/127.0.2/releasenotes/"},
+		{"127.0", "mozilla-release", "https://www.mozilla.org/en-US/firefox/127.0/releasenotes/"},
+		{"115.13.0esr", "mozilla-esr", "https://www.mozilla.org/en-US/firefox/115.13.0/releasenotes/"},
+	}
+
+	if len(releases) != len(want) {
+		t.Fatalf("Expected %d releases, got %d: %+v", len(want), len(releases), releases)
+	}
+	for i, w := range want {
+		r := releases[i]
+		if r.Version != w.version || r.Type != w.releaseType || r.URL != w.url {
+			t.Errorf("Release %d = {%s %s %s}, want %+v", i, r.Version, r.Type, r.URL, w)
+		}
+	}
+
+	if !releases[0].Prerelease || releases[0].Title != "Firefox Beta 129.0b2" {
+		t.Errorf("Unexpected beta release: %+v", releases[0])
+	}
+	if releases[5].Title != "Firefox ESR 115.13.0" || releases[5].Date.Format("2006-01-02") != "2024-07-09" {
+		t.Errorf("Unexpected ESR release: %+v", releases[5])
+	}
+}
+
+func TestSelectReleasesSkipsStaleBeta(t *testing.T) {
+	history := productHistory{Releases: map[string]productRelease{
+		"thunderbird-128.0":      {Category: "major", Date: "2024-07-11", Version: "128.0"},
+		"thunderbird-128.0b5":    {Category: "dev", Date: "2024-07-01", Version: "128.0b5"},
+		"thunderbird-128.1.0esr": {Category: "esr", Date: "2024-08-07", Version: "128.1.0esr"},
+	}}
+
+	releases := selectReleases(&history, products[1])
+	if len(releases) != 2 {
+		t.Fatalf("Expected release and ESR only, got %+v", releases)
+	}
+	if releases[0].URL != "https://www.thunderbird.net/en-US/thunderbird/128.1.0esr/releasenotes/" {
+		t.Errorf("Thunderbird ESR notes should keep the esr suffix, got %s", releases[0].URL)
+	}
+}
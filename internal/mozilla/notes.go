@@ -0,0 +1,33 @@
+package mozilla
+
+import (
+	"io"
+
+	"github.com/castrojo/bluefin-releases/internal/notespage"
+)
+
+// layouts are the release notes page layouts mozilla.org and thunderbird.net have used,
+// tried in order
+var layouts = []notespage.Layout{
+	// mozilla.org: li.release-note items under section headings (current pages) or tagged
+	// with their category (<b class="tag">Fixed</b>, older pages)
+	{
+		Section: "h2, h3, h4",
+		Item:    "li.release-note",
+		Content: ".release-note-content",
+		Tag:     ".tag",
+		Date:    "time[datetime], .c-release-date",
+	},
+	// thunderbird.net and other pages without release-note markup: plain lists under headings
+	{
+		Section: "h2, h3, h4",
+		Item:    "li",
+		Exclude: "nav, header, footer",
+		Date:    "time[datetime]",
+	},
+}
+
+// ParseReleaseNotes parses a Mozilla release notes page. pageURL resolves relative links.
+func ParseReleaseNotes(r io.Reader, pageURL string) (*notespage.Page, error) {
+	return notespage.Parse(r, pageURL, layouts)
+}
@@ -0,0 +1,84 @@
+package mozilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// goldenNotes is the golden-file form of a parsed release notes page
+type goldenNotes struct {
+	Date  string               `json:"date"`
+	Notes []models.ReleaseNote `json:"notes"`
+	HTML  string               `json:"html"`
+}
+
+func TestParseReleaseNotesGolden(t *testing.T) {
+	pages := map[string]string{
+		"firefox-128.0.html":        "https://www.mozilla.org/en-US/firefox/128.0/releasenotes/",
+		"firefox-60.0.html":         "https://www.mozilla.org/en-US/firefox/60.0/releasenotes/",
+		"thunderbird-128.0esr.html": "https://www.thunderbird.net/en-US/thunderbird/128.0esr/releasenotes/",
+	}
+
+	for page, pageURL := range pages {
+		t.Run(page, func(t *testing.T) {
+			file, err := os.Open(filepath.Join("testdata", page))
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			defer file.Close()
+
+			notes, err := ParseReleaseNotes(file, pageURL)
+			if err != nil {
+				t.Fatalf("ParseReleaseNotes failed: %v", err)
+			}
+
+			got := goldenNotes{Notes: notes.Structured(), HTML: notes.HTML()}
+			if !notes.Date.IsZero() {
+				got.Date = notes.Date.Format("2006-01-02")
+			}
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(got); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			gotJSON := bytes.TrimSpace(buf.Bytes())
+
+			goldenPath := filepath.Join("testdata", strings.TrimSuffix(page, ".html")+".golden.json")
+			if *update {
+				if err := os.WriteFile(goldenPath, append(gotJSON, '\n'), 0o644); err != nil {
+					t.Fatalf("Write golden file failed: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Read golden file failed (run with -update to create it): %v", err)
+			}
+			if strings.TrimSpace(string(want)) != string(gotJSON) {
+				t.Errorf("Parsed notes differ from %s (run with -update after checking the diff):\n%s", goldenPath, gotJSON)
+			}
+		})
+	}
+}
+
+func TestParseReleaseNotesUnknownLayout(t *testing.T) {
+	page := `<html><body><h1>Firefox</h1><ul><li>Download</li></ul><p>Nothing to see</p></body></html>`
+	notes, err := ParseReleaseNotes(strings.NewReader(page), "https://www.mozilla.org/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(notes.Notes) != 0 || notes.HTML() != "" {
+		t.Errorf("Expected no notes from an unrelated page, got %+v", notes.Notes)
+	}
+}
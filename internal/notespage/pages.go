@@ -0,0 +1,250 @@
+// Package notespage reads release notes from project websites. Each page source is declared
+// in pages.json by app ID: a URL template, which versions to fetch, and the CSS selectors of
+// the page's sections, items and release date.
+package notespage
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// Concurrency is the number of apps whose pages are fetched in parallel
+const Concurrency = 5
+
+// defaultLimit is the number of releases fetched when a source sets no limit
+const defaultLimit = 3
+
+// Version sources of a page
+const (
+	VersionsReleases = "releases" // The app's newest releases
+	VersionsFeature  = "feature"  // The app's newest feature releases (x.y.0), for pages shared by a series
+	VersionsApp      = "app"      // Only the app's current version
+)
+
+//go:embed pages.json
+var pagesJSON []byte
+
+// Source declares where an app's release notes pages are and how to read them
+type Source struct {
+	URL      string `json:"url"`             // Page URL with {version}, {major}, {minor} and {patch} placeholders
+	Versions string `json:"versions"`        // "releases" (default), "feature" or "app"
+	Limit    int    `json:"limit,omitempty"` // Releases fetched with "releases" or "feature" (default 3)
+	Layout   Layout `json:"layout"`
+	Notes    string `json:"notes"`
+}
+
+// Sources contains the page sources keyed by app ID
+type Sources struct {
+	Comment string            `json:"comment"`
+	Pages   map[string]Source `json:"pages"`
+}
+
+var (
+	sources     *Sources
+	sourcesOnce sync.Once
+)
+
+// loadSources loads the page sources from embedded JSON
+func loadSources() *Sources {
+	sourcesOnce.Do(func() {
+		var loaded Sources
+		if err := json.Unmarshal(pagesJSON, &loaded); err != nil {
+			log.Printf("Warning: Failed to load release notes pages: %v", err)
+			sources = &Sources{Pages: make(map[string]Source)}
+			return
+		}
+		sources = &loaded
+		log.Printf("Loaded %d release notes page sources", len(loaded.Pages))
+	})
+	return sources
+}
+
+// EnrichWithNotesPages fills release notes of apps declared in pages.json from their
+// project's release notes pages
+func EnrichWithNotesPages(apps []models.App) []models.App {
+	client := &http.Client{Timeout: 15 * time.Second}
+	return enrich(context.Background(), client, apps, loadSources().Pages)
+}
+
+// enrich fetches the pages of each app with a source and attaches their notes
+func enrich(ctx context.Context, client *http.Client, apps []models.App, pages map[string]Source) []models.App {
+	var (
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, Concurrency)
+		mu     sync.Mutex
+		filled int
+	)
+
+	for i := range apps {
+		app := &apps[i]
+		source, ok := pages[app.ID]
+		if !ok || len(app.Releases) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *models.App, source Source) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			n := enrichApp(ctx, client, app, source)
+			if n > 0 {
+				log.Printf("  %s: filled %d release notes from its website", app.ID, n)
+			}
+
+			mu.Lock()
+			filled += n
+			mu.Unlock()
+		}(app, source)
+	}
+
+	wg.Wait()
+	log.Printf("✅ Filled %d release notes from release notes pages", filled)
+	return apps
+}
+
+// enrichApp attaches page notes to the app's selected releases, fetching each page once.
+// Returns the number of releases filled.
+func enrichApp(ctx context.Context, client *http.Client, app *models.App, source Source) int {
+	pages := make(map[string]*Page)
+	filled := 0
+
+	for _, i := range selectReleases(app, source) {
+		release := &app.Releases[i]
+		pageURL, ok := ExpandURL(source.URL, release.Version)
+		if !ok {
+			continue
+		}
+
+		page, fetched := pages[pageURL]
+		if !fetched {
+			var err error
+			page, err = fetchPage(ctx, client, pageURL, source.Layout)
+			if err != nil {
+				log.Printf("⚠️  No release notes page for %s %s: %v", app.ID, release.Version, err)
+			}
+			pages[pageURL] = page
+		}
+		if page == nil || len(page.Notes) == 0 {
+			continue
+		}
+
+		// Notes from upstream (GitHub, changelogs, AppStream) are kept; the page only fills gaps
+		release.Notes = page.Structured()
+		if strings.TrimSpace(release.Description) == "" {
+			release.Description = page.HTML()
+		}
+		if release.Date.IsZero() {
+			release.Date = page.Date
+		}
+		filled++
+	}
+
+	return filled
+}
+
+// selectReleases returns the indexes of the releases a source fetches pages for
+func selectReleases(app *models.App, source Source) []int {
+	if source.Versions == VersionsApp {
+		current := versionKey(app.Version)
+		for i, release := range app.Releases {
+			if current != "" && versionKey(release.Version) == current {
+				return []int{i}
+			}
+		}
+		return nil
+	}
+
+	limit := source.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	var indexes []int
+	for i, release := range app.Releases {
+		if source.Versions == VersionsFeature && !isFeatureRelease(release.Version) {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return version.Compare(app.Releases[indexes[i]].Version, app.Releases[indexes[j]].Version) > 0
+	})
+	if len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+	return indexes
+}
+
+// isFeatureRelease reports whether a version starts a series: its patch number is 0 or missing
+// ("24.8.0.3" and "4.2" are, "24.8.1" isn't)
+func isFeatureRelease(v string) bool {
+	parsed, ok := version.Parse(v)
+	return ok && (len(parsed.Numbers) < 3 || parsed.Numbers[2] == 0)
+}
+
+// versionKey returns the normalized form of a version, or "" when it doesn't parse
+func versionKey(v string) string {
+	if parsed, ok := version.Parse(v); ok {
+		return parsed.Normalized()
+	}
+	return ""
+}
+
+// ExpandURL fills a page URL template for a version ("v24.8.2" fills {version} with "24.8.2",
+// {major} with "24", {minor} with "8" and {patch} with "2"). Missing components expand to "0".
+// Returns false when the version doesn't parse.
+func ExpandURL(template, v string) (string, bool) {
+	parsed, ok := version.Parse(v)
+	if !ok {
+		return "", false
+	}
+
+	component := func(i int) string {
+		if i < len(parsed.Numbers) {
+			return strconv.Itoa(parsed.Numbers[i])
+		}
+		return "0"
+	}
+
+	replacer := strings.NewReplacer(
+		"{version}", strings.TrimPrefix(strings.TrimSpace(v), parsed.Prefix),
+		"{major}", component(0),
+		"{minor}", component(1),
+		"{patch}", component(2),
+	)
+	return replacer.Replace(template), true
+}
+
+// fetchPage downloads and parses a release notes page
+func fetchPage(ctx context.Context, client *http.Client, pageURL string, layout Layout) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch release notes page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release notes page returned status %d", resp.StatusCode)
+	}
+
+	return Parse(resp.Body, pageURL, []Layout{layout})
+}
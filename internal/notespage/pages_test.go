@@ -0,0 +1,210 @@
+package notespage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+const wikiPage = `<html><body>
+<nav><ul><li>Main page</li></ul></nav>
+<div id="mw-content-text">
+  <div id="toc"><ul><li>Contents</li></ul></div>
+  <p>Released <time datetime="2024-08-22">August 22, 2024</time></p>
+  <h2>Writer</h2>
+  <ul>
+    <li>New "Legal" ring for <a href="/wiki/Writer">Writer</a> (<a href="https://bugs.documentfoundation.org/show_bug.cgi?id=160000">tdf#160000</a>)
+      <ul><li>Nested detail</li></ul>
+    </li>
+  </ul>
+  <h2>Bug Fixes</h2>
+  <ul><li>Crash when saving as <code>.docx</code></li></ul>
+  <h3>Animation &amp; Rigging</h3>
+  <ul><li>Bone collections can be nested</li></ul>
+</div>
+</body></html>`
+
+var wikiLayout = Layout{
+	Scope:      "#mw-content-text",
+	Section:    "h2, h3",
+	Item:       "li",
+	Exclude:    "#toc",
+	Date:       "time[datetime]",
+	AnySection: true,
+}
+
+func TestParseAnySection(t *testing.T) {
+	page, err := Parse(strings.NewReader(wikiPage), "https://wiki.example.org/ReleaseNotes/24.8", []Layout{wikiLayout})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if want := time.Date(2024, 8, 22, 0, 0, 0, 0, time.UTC); !page.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", page.Date, want)
+	}
+
+	got := make([]string, len(page.Notes))
+	for i, note := range page.Notes {
+		got[i] = note.Category + ": " + note.Markdown
+	}
+	want := []string{
+		`writer: New "Legal" ring for [Writer](https://wiki.example.org/wiki/Writer) ([tdf#160000](https://bugs.documentfoundation.org/show_bug.cgi?id=160000)) Nested detail`,
+		"fixed: Crash when saving as `.docx`",
+		"animation-rigging: Bone collections can be nested",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("notes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if bugs := page.Notes[0].Bugs; len(bugs) != 1 || bugs[0] != 160000 {
+		t.Errorf("Bugs = %v, want [160000]", bugs)
+	}
+
+	// Known categories render first, custom sections follow in page order under their heading
+	html := page.HTML()
+	fixed, writer, animation := strings.Index(html, "Fixed"), strings.Index(html, "Writer</h2>"), strings.Index(html, "Animation")
+	if fixed < 0 || writer < 0 || animation < 0 || !(fixed < writer && writer < animation) {
+		t.Errorf("HTML sections out of order:\n%s", html)
+	}
+}
+
+func TestParseUnknownSectionsSkipped(t *testing.T) {
+	layout := wikiLayout
+	layout.AnySection = false
+
+	page, err := Parse(strings.NewReader(wikiPage), "https://wiki.example.org/", []Layout{layout})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(page.Notes) != 1 || page.Notes[0].Category != "fixed" {
+		t.Errorf("Notes = %+v, want only the Bug Fixes note", page.Notes)
+	}
+}
+
+func TestParseEscapesPageText(t *testing.T) {
+	page, err := Parse(strings.NewReader(`<div id="mw-content-text"><h2>Bug Fixes</h2>
+<ul><li>Globs like *.odt and my_file_name, [tdf] tags and &lt;video&gt; elements work</li></ul></div>`),
+		"https://wiki.example.org/", []Layout{wikiLayout})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	html := page.HTML()
+	for _, want := range []string{"*.odt", "my_file_name", "[tdf]", "&lt;video&gt;"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML lost %q:\n%s", want, html)
+		}
+	}
+}
+
+func TestExpandURL(t *testing.T) {
+	tests := []struct {
+		template, version, want string
+	}{
+		{"https://example.org/ReleaseNotes/{major}.{minor}", "24.8.2", "https://example.org/ReleaseNotes/24.8"},
+		{"https://example.org/{version}/notes", "v4.2.1", "https://example.org/4.2.1/notes"},
+		{"https://example.org/{major}.{minor}.{patch}", "31", "https://example.org/31.0.0"},
+	}
+	for _, tt := range tests {
+		got, ok := ExpandURL(tt.template, tt.version)
+		if !ok || got != tt.want {
+			t.Errorf("ExpandURL(%q, %q) = %q, %v; want %q", tt.template, tt.version, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := ExpandURL("https://example.org/{version}", "nightly"); ok {
+		t.Error("ExpandURL accepted a version without numbers")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.URL.Path != "/ReleaseNotes/24.8" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(wikiPage))
+	}))
+	defer server.Close()
+
+	apps := []models.App{{
+		ID: "org.example.Office",
+		Releases: []models.Release{
+			{Version: "24.2.5", Description: "<p>Old notes</p>"},
+			{Version: "24.8.1", Description: "<p>Upstream notes</p>"},
+			{Version: "24.8.0", Date: time.Date(2024, 8, 21, 0, 0, 0, 0, time.UTC)},
+		},
+	}}
+	pages := map[string]Source{
+		"org.example.Office": {URL: server.URL + "/ReleaseNotes/{major}.{minor}", Limit: 2, Layout: wikiLayout},
+	}
+
+	apps = enrich(context.Background(), server.Client(), apps, pages)
+	releases := apps[0].Releases
+
+	if releases[0].Description != "<p>Old notes</p>" {
+		t.Errorf("release beyond the limit was changed: %q", releases[0].Description)
+	}
+	if len(releases[1].Notes) != 3 || releases[1].Description != "<p>Upstream notes</p>" {
+		t.Errorf("24.8.1 upstream notes replaced or page notes missing: %d notes, %q", len(releases[1].Notes), releases[1].Description)
+	}
+	if len(releases[2].Notes) != 3 || !strings.Contains(releases[2].Description, "Bone collections") {
+		t.Errorf("24.8.0 not filled from the page: %d notes, %q", len(releases[2].Notes), releases[2].Description)
+	}
+	if want := time.Date(2024, 8, 22, 0, 0, 0, 0, time.UTC); !releases[1].Date.Equal(want) {
+		t.Errorf("missing date not filled from the page: %v", releases[1].Date)
+	}
+	if want := time.Date(2024, 8, 21, 0, 0, 0, 0, time.UTC); !releases[2].Date.Equal(want) {
+		t.Errorf("existing date replaced: %v", releases[2].Date)
+	}
+	if requests["/ReleaseNotes/24.8"] != 1 {
+		t.Errorf("page shared by two releases fetched %d times, want 1", requests["/ReleaseNotes/24.8"])
+	}
+}
+
+func TestSelectReleasesAppVersion(t *testing.T) {
+	app := &models.App{
+		Version:  "v30.2",
+		Releases: []models.Release{{Version: "31.0.0"}, {Version: "30.2.0"}, {Version: "30.1.0"}},
+	}
+	got := selectReleases(app, Source{Versions: VersionsApp})
+	if len(got) != 1 || got[0] != 1 {
+		t.Errorf("selectReleases = %v, want [1]", got)
+	}
+}
+
+func TestSelectReleasesFeature(t *testing.T) {
+	app := &models.App{
+		Releases: []models.Release{{Version: "25.2.1.2"}, {Version: "25.2.0.3"}, {Version: "24.8.4"}, {Version: "24.8.0"}, {Version: "24.2"}},
+	}
+	got := selectReleases(app, Source{Versions: VersionsFeature, Limit: 2})
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("selectReleases = %v, want [1 3]", got)
+	}
+}
+
+func TestEmbeddedSources(t *testing.T) {
+	var loaded Sources
+	if err := json.Unmarshal(pagesJSON, &loaded); err != nil {
+		t.Fatalf("pages.json: %v", err)
+	}
+	for appID, source := range loaded.Pages {
+		if source.URL == "" || source.Layout.Section == "" || source.Layout.Item == "" {
+			t.Errorf("%s: url, layout.section and layout.item are required", appID)
+		}
+		if source.Versions != "" && source.Versions != VersionsReleases && source.Versions != VersionsFeature && source.Versions != VersionsApp {
+			t.Errorf("%s: unknown versions source %q", appID, source.Versions)
+		}
+		if _, ok := ExpandURL(source.URL, "1.2.3"); !ok {
+			t.Errorf("%s: URL template does not expand", appID)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package notespage
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/castrojo/bluefin-releases/internal/markdown"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"golang.org/x/net/html"
+)
+
+// Layout describes where a release notes page keeps its notes, as CSS selectors.
+// Items are read in document order; each belongs to the section of the last heading before it.
+type Layout struct {
+	Scope      string `json:"scope,omitempty"`      // Container holding the notes (default: whole page)
+	Section    string `json:"section"`              // Section headings ("h2, h3")
+	Item       string `json:"item"`                 // Note items ("li.release-note", "li")
+	Content    string `json:"content,omitempty"`    // Element inside an item holding its text (default: the item)
+	Tag        string `json:"tag,omitempty"`        // Element inside an item naming its category ("b.tag")
+	Exclude    string `json:"exclude,omitempty"`    // Items inside these elements are skipped ("nav, footer")
+	Date       string `json:"date,omitempty"`       // Element with the release date (datetime attribute or text)
+	AnySection bool   `json:"anySection,omitempty"` // Keep sections with unknown headings, named after the heading
+}
+
+// Page is the parsed content of a release notes page
+type Page struct {
+	Date  time.Time // Zero when the page shows no release date
+	Notes []Note
+}
+
+// Note is a release note with its section title and content rendered as Markdown
+type Note struct {
+	models.ReleaseNote
+	Section  string // Heading the note appeared under
+	Markdown string
+}
+
+// categories maps section headings and item tags to note category slugs
+var categories = map[string]string{
+	"new":                     "new",
+	"fixed":                   "fixed",
+	"fixes":                   "fixed",
+	"bug fixes":               "fixed",
+	"changed":                 "changed",
+	"changes":                 "changed",
+	"enterprise":              "enterprise",
+	"developer":               "developer",
+	"developers":              "developer",
+	"web platform":            "web-platform",
+	"html5":                   "web-platform",
+	"security":                "security",
+	"security fixes":          "security",
+	"known issues":            "known-issues",
+	"unresolved":              "unresolved",
+	"community contributions": "community",
+}
+
+// categoryTitles are the headings of known categories when rendering notes, in display order.
+// Sections with other headings follow in page order.
+var categoryTitles = []struct{ slug, title string }{
+	{"new", "New"},
+	{"fixed", "Fixed"},
+	{"changed", "Changed"},
+	{"enterprise", "Enterprise"},
+	{"developer", "Developer"},
+	{"web-platform", "Web Platform"},
+	{"security", "Security Fixes"},
+	{"known-issues", "Known Issues"},
+	{"unresolved", "Unresolved"},
+	{"community", "Community Contributions"},
+}
+
+// dateLayouts are the date formats accepted in date elements
+var dateLayouts = []string{"2006-01-02", time.RFC3339, "January 2, 2006", "Jan 2, 2006", "2 January 2006"}
+
+// bugLinkRe finds bug numbers in tracker links (Bugzilla show_bug.cgi?id=N, bugzilla.mozilla.org/N,
+// bugzil.la/N, bugs.documentfoundation.org, and /issues/N or /pull/N on forges)
+var bugLinkRe = regexp.MustCompile(`(?:/show_bug\.cgi\?id=|bugzilla\.mozilla\.org/|bugzil\.la/|/issues/|/pull/|tdf#)(\d+)`)
+
+// bugTextRe finds bug numbers mentioned in text ("bug 1234567", "tdf#160000")
+var bugTextRe = regexp.MustCompile(`(?i)(?:\bbug\s+#?|\btdf#)(\d{4,})\b`)
+
+// releasedRe finds a release date written in the page ("Released July 9, 2024",
+// "first offered to Release channel users on July 11, 2024")
+var releasedRe = regexp.MustCompile(`(?:Released|offered to [^.]*? on)\s+((?:January|February|March|April|May|June|July|August|September|October|November|December)\s+\d{1,2},\s+\d{4})`)
+
+// Parse parses a release notes page, trying each layout in turn; the first layout that finds
+// notes wins. pageURL resolves relative links.
+func Parse(r io.Reader, pageURL string, layouts []Layout) (*Page, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse release notes: %w", err)
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse page URL: %w", err)
+	}
+
+	page := &Page{}
+	for _, layout := range layouts {
+		if page.Date.IsZero() {
+			page.Date = pageDate(doc, layout.Date)
+		}
+		if notes := layout.notes(doc, base); len(notes) > 0 {
+			page.Notes = notes
+			break
+		}
+	}
+	return page, nil
+}
+
+// Structured returns the notes without their rendered Markdown
+func (p *Page) Structured() []models.ReleaseNote {
+	result := make([]models.ReleaseNote, len(p.Notes))
+	for i, note := range p.Notes {
+		result[i] = note.ReleaseNote
+	}
+	return result
+}
+
+// HTML renders the notes as HTML, grouped under a heading per category
+func (p *Page) HTML() string {
+	type section struct{ slug, title string }
+	var sections []section
+	for _, category := range categoryTitles {
+		sections = append(sections, section{category.slug, category.title})
+	}
+	known := make(map[string]bool)
+	for _, s := range sections {
+		known[s.slug] = true
+	}
+	for _, note := range p.Notes {
+		if !known[note.Category] {
+			known[note.Category] = true
+			sections = append(sections, section{note.Category, note.Section})
+		}
+	}
+
+	var parts []string
+	for _, s := range sections {
+		var items []string
+		for _, note := range p.Notes {
+			if note.Category == s.slug {
+				items = append(items, "- "+note.Markdown)
+			}
+		}
+		if len(items) > 0 {
+			parts = append(parts, "## "+s.title+"\n\n"+strings.Join(items, "\n"))
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return markdown.ToHTML(strings.Join(parts, "\n\n"))
+}
+
+// notes walks section headings and items in document order
+func (l Layout) notes(doc *goquery.Document, base *url.URL) []Note {
+	scope := doc.Selection
+	if l.Scope != "" {
+		scope = doc.Find(l.Scope).First()
+	}
+
+	var notes []Note
+	var items []*html.Node
+	category, title := "", ""
+
+	scope.Find(l.Section + ", " + l.Item).Each(func(_ int, s *goquery.Selection) {
+		if !s.Is(l.Item) {
+			category, title = l.sectionCategory(s.Text())
+			return
+		}
+
+		// Nested items stay part of their parent item
+		for _, item := range items {
+			if s.ParentsFiltered("*").IndexOfNode(item) >= 0 {
+				return
+			}
+		}
+		if l.Exclude != "" && s.ParentsFiltered(l.Exclude).Length() > 0 {
+			return
+		}
+		items = append(items, s.Get(0))
+
+		itemCategory, itemTitle := category, title
+		if l.Tag != "" {
+			if tag := s.Find(l.Tag).First(); tag.Length() > 0 {
+				if slug, ok := categorySlug(tag.Text()); ok {
+					itemCategory, itemTitle = slug, strings.TrimSpace(tag.Text())
+				}
+				tag.Remove()
+			}
+		}
+		if itemCategory == "" {
+			return
+		}
+
+		content := s
+		if l.Content != "" {
+			if found := s.Find(l.Content).First(); found.Length() > 0 {
+				content = found
+			}
+		}
+		if note, ok := newNote(itemCategory, itemTitle, content, base); ok {
+			notes = append(notes, note)
+		}
+	})
+
+	return notes
+}
+
+// sectionCategory returns the category and title a section heading starts.
+// Unknown headings end the current section unless the layout keeps any section.
+func (l Layout) sectionCategory(heading string) (string, string) {
+	title := strings.Join(strings.Fields(heading), " ")
+	if slug, ok := categorySlug(title); ok {
+		return slug, title
+	}
+	if l.AnySection && title != "" {
+		return slugify(title), title
+	}
+	return "", ""
+}
+
+// categorySlug maps a heading or tag ("What’s New", "Fixed:", "Web Platform") to a category slug
+func categorySlug(heading string) (string, bool) {
+	text := strings.ToLower(strings.Join(strings.Fields(heading), " "))
+	text = strings.TrimSuffix(text, ":")
+	for _, prefix := range []string{"what's ", "what’s "} {
+		text = strings.TrimPrefix(text, prefix)
+	}
+	slug, ok := categories[text]
+	return slug, ok
+}
+
+// slugify turns a heading into a category slug ("Animation & Rigging" -> "animation-rigging")
+func slugify(heading string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(heading) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// newNote builds a note from an item's content element
+func newNote(category, section string, content *goquery.Selection, base *url.URL) (Note, bool) {
+	text := strings.Join(strings.Fields(content.Text()), " ")
+	if text == "" {
+		return Note{}, false
+	}
+
+	note := Note{
+		ReleaseNote: models.ReleaseNote{Category: category, Text: text},
+		Section:     section,
+		Markdown:    strings.Join(strings.Fields(inlineMarkdown(content, base)), " "),
+	}
+
+	bugs := make(map[int]bool)
+	content.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href := resolveLink(a.AttrOr("href", ""), base)
+		if href == "" {
+			return
+		}
+		note.Links = append(note.Links, href)
+		if match := bugLinkRe.FindStringSubmatch(href); match != nil {
+			if n, err := strconv.Atoi(match[1]); err == nil {
+				bugs[n] = true
+			}
+		}
+	})
+	for _, match := range bugTextRe.FindAllStringSubmatch(text, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil {
+			bugs[n] = true
+		}
+	}
+
+	for n := range bugs {
+		note.Bugs = append(note.Bugs, n)
+	}
+	sort.Ints(note.Bugs)
+
+	return note, true
+}
+
+// markdownEscaper escapes page text that Markdown would otherwise read as emphasis, links,
+// code or raw HTML
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"`", "\\`",
+	"<", `\<`,
+)
+
+// inlineMarkdown renders an element's content as inline Markdown, keeping links and code
+func inlineMarkdown(s *goquery.Selection, base *url.URL) string {
+	var b strings.Builder
+	s.Contents().Each(func(_ int, child *goquery.Selection) {
+		node := child.Get(0)
+		switch {
+		case node.Type == html.TextNode:
+			b.WriteString(markdownEscaper.Replace(node.Data))
+		case node.Type != html.ElementNode:
+		case node.Data == "a":
+			text := strings.TrimSpace(inlineMarkdown(child, base))
+			if href := resolveLink(child.AttrOr("href", ""), base); href != "" && text != "" {
+				fmt.Fprintf(&b, "[%s](%s)", text, href)
+			} else {
+				b.WriteString(text)
+			}
+		case node.Data == "code":
+			fmt.Fprintf(&b, "`%s`", child.Text())
+		case node.Data == "br", node.Data == "p", node.Data == "div", node.Data == "li":
+			b.WriteString(" " + inlineMarkdown(child, base) + " ")
+		default:
+			b.WriteString(inlineMarkdown(child, base))
+		}
+	})
+	return b.String()
+}
+
+// resolveLink resolves an href against the page URL, dropping fragment-only and script links
+func resolveLink(href string, base *url.URL) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	return resolved.String()
+}
+
+// pageDate finds the release date shown on the page: the layout's date element
+// (datetime attribute or text), then a "Released <date>" sentence
+func pageDate(doc *goquery.Document, selector string) time.Time {
+	var candidates []string
+	if selector != "" {
+		element := doc.Find(selector).First()
+		if datetime, ok := element.Attr("datetime"); ok {
+			candidates = append(candidates, datetime)
+		}
+		candidates = append(candidates, strings.Join(strings.Fields(element.Text()), " "))
+	}
+	if match := releasedRe.FindStringSubmatch(strings.Join(strings.Fields(doc.Text()), " ")); match != nil {
+		candidates = append(candidates, match[1])
+	}
+
+	for _, candidate := range candidates {
+		for _, layout := range dateLayouts {
+			if date, err := time.Parse(layout, candidate); err == nil {
+				return date
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,157 @@
+package osv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// APIURL is the OSV API (https://google.github.io/osv.dev/api/)
+var APIURL = "https://api.osv.dev/v1"
+
+// apiConcurrency limits parallel record downloads
+const apiConcurrency = 10
+
+// apiBatchSize is the most queries the querybatch endpoint accepts per request
+const apiBatchSize = 1000
+
+// apiQuery asks for the records affecting one package version
+type apiQuery struct {
+	Package Package `json:"package"`
+	Version string  `json:"version"`
+}
+
+// apiBatchResponse lists the IDs of the records matching each query, in query order
+type apiBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+func apiClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// apiQueries returns the OSV API queries for an app's shipped version. The API only knows
+// OSV ecosystems, so apps are queried by source repository (GIT) and, for GitHub repos, Go
+// module; Homebrew and Flatpak identifiers need a local dump.
+func apiQueries(app models.App) []apiQuery {
+	shipped := ShippedVersion(app)
+	repo := app.SourceRepo
+	if shipped == "" || repo == nil || repo.URL == "" {
+		return nil
+	}
+
+	queries := []apiQuery{{Package: Package{Ecosystem: "GIT", Name: strings.TrimSuffix(repo.URL, ".git")}, Version: shipped}}
+	if repo.Type == "github" && repo.Owner != "" && repo.Repo != "" {
+		module := "github.com/" + repo.Owner + "/" + repo.Repo
+		queries = append(queries, apiQuery{Package: Package{Ecosystem: "Go", Name: module}, Version: "v" + strings.TrimPrefix(shipped, "v")})
+	}
+	return queries
+}
+
+// queryAPI asks the OSV API which records affect the apps' shipped versions and adds the
+// records not already in db
+func queryAPI(client *http.Client, apps []models.App, db *Database) error {
+	var queries []apiQuery
+	for _, app := range apps {
+		queries = append(queries, apiQueries(app)...)
+	}
+
+	ids := make(map[string]bool)
+	for start := 0; start < len(queries); start += apiBatchSize {
+		end := min(start+apiBatchSize, len(queries))
+		batch, err := queryBatch(client, queries[start:end])
+		if err != nil {
+			return err
+		}
+		for _, result := range batch.Results {
+			for _, vuln := range result.Vulns {
+				if db.vulns[vuln.ID] == nil {
+					ids[vuln.ID] = true
+				}
+			}
+		}
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, apiConcurrency)
+	)
+	for id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			record, err := fetchVulnerability(client, id)
+			if err != nil {
+				log.Printf("⚠️  Failed to fetch OSV record %s: %v", id, err)
+				return
+			}
+
+			mu.Lock()
+			db.Add(record)
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	log.Printf("Fetched %d OSV records for %d queries from the OSV API", len(ids), len(queries))
+	return nil
+}
+
+// queryBatch sends one querybatch request
+func queryBatch(client *http.Client, queries []apiQuery) (*apiBatchResponse, error) {
+	body, err := json.Marshal(map[string][]apiQuery{"queries": queries})
+	if err != nil {
+		return nil, fmt.Errorf("encode queries: %w", err)
+	}
+
+	resp, err := client.Post(APIURL+"/querybatch", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("query OSV: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV querybatch returned status %d", resp.StatusCode)
+	}
+
+	var batch apiBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("decode querybatch response: %w", err)
+	}
+	return &batch, nil
+}
+
+// fetchVulnerability downloads one full record
+func fetchVulnerability(client *http.Client, id string) (Vulnerability, error) {
+	resp, err := client.Get(APIURL + "/vulns/" + url.PathEscape(id))
+	if err != nil {
+		return Vulnerability{}, fmt.Errorf("fetch record: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Vulnerability{}, fmt.Errorf("record returned status %d", resp.StatusCode)
+	}
+
+	var record Vulnerability
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		return Vulnerability{}, fmt.Errorf("decode record: %w", err)
+	}
+	return record, nil
+}
@@ -0,0 +1,276 @@
+package osv
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// VulnerabilityURL is the osv.dev page of a record
+const VulnerabilityURL = "https://osv.dev/vulnerability/"
+
+// Identifiers returns the index keys an app is known by: its Homebrew formula or Flatpak ID,
+// its source repository, and for GitHub repos the github purl and Go module path
+func Identifiers(app models.App) []string {
+	var keys []string
+	switch app.PackageType {
+	case "homebrew":
+		if app.HomebrewInfo != nil && app.HomebrewInfo.Formula != "" {
+			keys = append(keys, packageKey("Homebrew", app.HomebrewInfo.Formula))
+		}
+	case "flatpak":
+		keys = append(keys, packageKey("Flatpak", app.ID))
+	}
+
+	if repo := app.SourceRepo; repo != nil && repo.URL != "" {
+		keys = append(keys, repoKey(repo.URL))
+		if repo.Type == "github" && repo.Owner != "" && repo.Repo != "" {
+			path := repo.Owner + "/" + repo.Repo
+			keys = append(keys, purlKey("pkg:github/"+path), packageKey("Go", "github.com/"+path))
+		}
+	}
+	return keys
+}
+
+// ShippedVersion returns the version Bluefin users get: the packaged version for Homebrew,
+// the newest AppStream release for Flatpaks (the build on Flathub), or "" when unknown.
+// OS images have no upstream package to match.
+func ShippedVersion(app models.App) string {
+	switch app.PackageType {
+	case "homebrew":
+		if app.HomebrewInfo != nil && len(app.HomebrewInfo.Versions) > 0 {
+			return app.HomebrewInfo.Versions[0]
+		}
+		return ""
+	case "os":
+		return ""
+	case "flatpak":
+		for _, release := range app.Releases {
+			if fromAppStream(release) {
+				return release.Version
+			}
+		}
+	}
+	return app.Version
+}
+
+// fromAppStream reports whether a release was reported by Flathub's AppStream data
+func fromAppStream(release models.Release) bool {
+	if release.Type == "appstream" {
+		return true
+	}
+	for _, source := range release.Sources {
+		if source.Type == "appstream" {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the records of db affecting the app's shipped version. Each record's fix is
+// looked up among the app's releases (sorted newest first); the fixing release gets the record
+// as a security advisory.
+func Match(app *models.App, db *Database) []models.Vulnerability {
+	shipped := ShippedVersion(*app)
+	current, ok := version.Parse(shipped)
+	if !ok {
+		return nil
+	}
+
+	keys := Identifiers(*app)
+	appKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		appKeys[key] = true
+	}
+
+	var result []models.Vulnerability
+	for _, record := range db.lookup(keys) {
+		for _, affected := range record.Affected {
+			if !matchesAny(affectedKeys(affected), appKeys) {
+				continue
+			}
+			hit, fixed, gitFix := affects(affected, current)
+			if !hit {
+				continue
+			}
+
+			vuln := models.Vulnerability{
+				ID:       record.ID,
+				Aliases:  record.Aliases,
+				Summary:  record.Summary,
+				URL:      VulnerabilityURL + record.ID,
+				Severity: severity(record, affected),
+				Version:  shipped,
+				Fixed:    fixed,
+			}
+			if i := fixingRelease(app.Releases, current, fixed, affected, gitFix); i >= 0 {
+				vuln.FixedBy = app.Releases[i].Version
+				attachAdvisory(&app.Releases[i], vuln)
+			}
+			result = append(result, vuln)
+			break
+		}
+	}
+	return result
+}
+
+// matchesAny reports whether any key is one of the app's
+func matchesAny(keys []string, appKeys map[string]bool) bool {
+	for _, key := range keys {
+		if appKeys[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// affects reports whether v is affected, either listed in Versions or inside a SEMVER or
+// ECOSYSTEM range, with the lowest fixed version above v. gitFix reports that a GIT range
+// has a fix commit, whose version is only known from the tags Versions leaves out.
+func affects(affected Affected, v version.Version) (hit bool, fixed string, gitFix bool) {
+	for _, listed := range affected.Versions {
+		if lv, ok := version.Parse(listed); ok && lv.Normalized() == v.Normalized() {
+			hit = true
+		}
+	}
+
+	var lowest version.Version
+	for _, r := range affected.Ranges {
+		if r.Type == "GIT" {
+			for _, event := range r.Events {
+				gitFix = gitFix || event.Fixed != ""
+			}
+			continue
+		}
+		if inRange(r.Events, v) {
+			hit = true
+		}
+		for _, event := range r.Events {
+			fv, ok := version.Parse(event.Fixed)
+			if ok && fv.Compare(v) > 0 && (fixed == "" || fv.Compare(lowest) < 0) {
+				fixed, lowest = event.Fixed, fv
+			}
+		}
+	}
+	return hit, fixed, gitFix
+}
+
+// inRange evaluates range events in version order: v is affected from an introduced version
+// up to (excluding) a fixed version or up to (including) a last_affected version
+func inRange(events []Event, v version.Version) bool {
+	affected := false
+	for _, event := range sortedEvents(events) {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || compare(v, event.Introduced) >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if compare(v, event.Fixed) >= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if compare(v, event.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// sortedEvents orders events by version, introduced "0" first
+func sortedEvents(events []Event) []Event {
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return eventLess(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// eventLess orders two events by their version
+func eventLess(a, b Event) bool {
+	av, bv := eventVersion(a), eventVersion(b)
+	if av == "0" || bv == "0" {
+		return av == "0" && bv != "0"
+	}
+	return version.Compare(av, bv) < 0
+}
+
+// eventVersion returns the version an event is at
+func eventVersion(event Event) string {
+	for _, s := range []string{event.Introduced, event.Fixed, event.LastAffected, event.Limit} {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// compare compares a parsed version with a version string; unparseable strings sort lowest
+func compare(v version.Version, s string) int {
+	o, ok := version.Parse(s)
+	if !ok {
+		return 1
+	}
+	return v.Compare(o)
+}
+
+// fixingRelease returns the index of the oldest stable release newer than current that fixes
+// the record: the first at or above the fixed version, or for GIT ranges the first not listed
+// as affected. Returns -1 when no tracked release has the fix.
+func fixingRelease(releases []models.Release, current version.Version, fixed string, affected Affected, gitFix bool) int {
+	if fixed == "" && !gitFix {
+		return -1
+	}
+
+	listed := make(map[string]bool)
+	for _, s := range affected.Versions {
+		if v, ok := version.Parse(s); ok {
+			listed[v.Normalized()] = true
+		}
+	}
+
+	found := -1
+	for i, release := range releases {
+		v, ok := version.Parse(release.Version)
+		if !ok || release.Prerelease || v.Compare(current) <= 0 {
+			continue
+		}
+		if (fixed != "" && compare(v, fixed) >= 0) || (fixed == "" && !listed[v.Normalized()]) {
+			found = i // Releases sort newest first, so the last hit is the oldest
+		}
+	}
+	return found
+}
+
+// severity returns the record's GitHub advisory rating in lower case ("high", "moderate")
+func severity(record *Vulnerability, affected Affected) string {
+	rating := record.DatabaseSpecific.Severity
+	if rating == "" {
+		rating = affected.DatabaseSpecific.Severity
+	}
+	return strings.ToLower(rating)
+}
+
+// attachAdvisory records a vulnerability as a security advisory fixed by a release
+func attachAdvisory(release *models.Release, vuln models.Vulnerability) {
+	for _, advisory := range release.Advisories {
+		if advisory.ID == vuln.ID {
+			return
+		}
+	}
+
+	advisory := models.SecurityAdvisory{
+		ID:     vuln.ID,
+		Title:  vuln.Summary,
+		URL:    vuln.URL,
+		Impact: vuln.Severity,
+	}
+	for _, alias := range vuln.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			advisory.CVEs = append(advisory.CVEs, models.AdvisoryCVE{ID: alias, Title: vuln.Summary, Impact: vuln.Severity})
+		}
+	}
+	release.Advisories = append(release.Advisories, advisory)
+	release.Security = true
+}
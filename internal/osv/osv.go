@@ -0,0 +1,291 @@
+// Package osv flags apps whose shipped version has known vulnerabilities, matching OSV
+// (https://osv.dev) records against each app's package identifiers: Homebrew formula,
+// Flatpak app ID, source repository (GIT ranges) and Go module. Records come from a local
+// OSV dump and, optionally, the OSV API.
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Vulnerability is an OSV record (the fields used for matching)
+type Vulnerability struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Withdrawn        string           `json:"withdrawn"`
+	Affected         []Affected       `json:"affected"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+// Affected lists the affected versions of one package
+type Affected struct {
+	Package          Package          `json:"package"`
+	Ranges           []Range          `json:"ranges"`
+	Versions         []string         `json:"versions"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+// Package identifies a package in an ecosystem ("Go" "github.com/cli/cli", "Homebrew" "bat")
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl,omitempty"`
+}
+
+// Range is a span of affected versions: SEMVER and ECOSYSTEM ranges use versions,
+// GIT ranges use commits of Repo
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo"`
+	Events []Event `json:"events"`
+}
+
+// Event is one boundary of a range; exactly one field is set
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// DatabaseSpecific holds the severity rating GitHub advisories add ("HIGH", "MODERATE")
+type DatabaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+// Database indexes OSV records by the packages and repositories they affect
+type Database struct {
+	vulns map[string]*Vulnerability
+	index map[string][]*Vulnerability // Package or repo key -> records affecting it
+}
+
+// NewDatabase returns an empty database
+func NewDatabase() *Database {
+	return &Database{
+		vulns: make(map[string]*Vulnerability),
+		index: make(map[string][]*Vulnerability),
+	}
+}
+
+// Len returns the number of records in the database
+func (db *Database) Len() int {
+	return len(db.vulns)
+}
+
+// Add indexes a record. Withdrawn records are skipped and records already present are kept.
+func (db *Database) Add(v Vulnerability) {
+	if v.ID == "" || v.Withdrawn != "" || db.vulns[v.ID] != nil {
+		return
+	}
+	db.vulns[v.ID] = &v
+
+	keys := make(map[string]bool)
+	for _, affected := range v.Affected {
+		for _, key := range affectedKeys(affected) {
+			keys[key] = true
+		}
+	}
+	for key := range keys {
+		db.index[key] = append(db.index[key], &v)
+	}
+}
+
+// affectedKeys returns the index keys of an affected package: its ecosystem name, purl and
+// the repositories of its GIT ranges
+func affectedKeys(affected Affected) []string {
+	var keys []string
+	switch {
+	case affected.Package.Ecosystem == "GIT":
+		// GIT packages are named by repository URL
+		keys = append(keys, repoKey(affected.Package.Name))
+	case affected.Package.Name != "":
+		keys = append(keys, packageKey(affected.Package.Ecosystem, affected.Package.Name))
+	}
+	if affected.Package.PURL != "" {
+		keys = append(keys, purlKey(affected.Package.PURL))
+	}
+	for _, r := range affected.Ranges {
+		if r.Type == "GIT" && r.Repo != "" {
+			keys = append(keys, repoKey(r.Repo))
+		}
+	}
+	return keys
+}
+
+// lookup returns the records indexed under any of the keys, once each, sorted by ID
+func (db *Database) lookup(keys []string) []*Vulnerability {
+	seen := make(map[string]bool)
+	var result []*Vulnerability
+	for _, key := range keys {
+		for _, v := range db.index[key] {
+			if !seen[v.ID] {
+				seen[v.ID] = true
+				result = append(result, v)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// packageKey is the index key of an ecosystem package
+func packageKey(ecosystem, name string) string {
+	return strings.ToLower(ecosystem) + ":" + strings.ToLower(name)
+}
+
+// purlKey is the index key of a package URL, without version or qualifiers
+// ("pkg:github/cli/cli@2.40.0" -> "purl:pkg:github/cli/cli")
+func purlKey(purl string) string {
+	key := strings.ToLower(purl)
+	if i := strings.IndexAny(key, "@?#"); i >= 0 {
+		key = key[:i]
+	}
+	return "purl:" + key
+}
+
+// repoKey is the index key of a repository URL, ignoring scheme, case and a ".git" suffix
+func repoKey(repoURL string) string {
+	key := strings.ToLower(strings.TrimSpace(repoURL))
+	if i := strings.Index(key, "://"); i >= 0 {
+		key = key[i+3:]
+	}
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "/"), ".git")
+	return "git:" + key
+}
+
+// LoadDump loads OSV records from a local dump: a directory of JSON files (searched
+// recursively), a zip archive as published at osv-vulnerabilities.storage.googleapis.com,
+// or a single JSON file holding one record or an array of records
+func LoadDump(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("open OSV dump: %w", err)
+	}
+
+	db := NewDatabase()
+	switch {
+	case info.IsDir():
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(file, ".json") {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return db.addJSON(file, data)
+		})
+	case strings.HasSuffix(path, ".zip"):
+		err = db.addZip(path)
+	default:
+		var data []byte
+		if data, err = os.ReadFile(path); err == nil {
+			err = db.addJSON(path, data)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load OSV dump: %w", err)
+	}
+	return db, nil
+}
+
+// addZip adds every JSON record of a zip archive
+func (db *Database) addZip(path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := db.addJSON(file.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addJSON adds one record or an array of records
+func (db *Database) addJSON(name string, data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		var vulns []Vulnerability
+		if err := json.Unmarshal(data, &vulns); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, v := range vulns {
+			db.Add(v)
+		}
+		return nil
+	}
+
+	var v Vulnerability
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	db.Add(v)
+	return nil
+}
+
+// EnrichWithVulnerabilities flags apps whose shipped version is affected by records of the
+// OSV dump at dumpPath (skipped when empty) and, when useAPI is set, of the OSV API.
+// Releases sort newest first, so this runs after releases are normalized.
+func EnrichWithVulnerabilities(apps []models.App, dumpPath string, useAPI bool) []models.App {
+	if dumpPath == "" && !useAPI {
+		log.Println("No OSV dump or API configured, skipping vulnerability check")
+		return apps
+	}
+
+	db := NewDatabase()
+	if dumpPath != "" {
+		loaded, err := LoadDump(dumpPath)
+		if err != nil {
+			log.Printf("⚠️  %v", err)
+		} else {
+			db = loaded
+			log.Printf("Loaded %d OSV records from %s", db.Len(), dumpPath)
+		}
+	}
+	if useAPI {
+		if err := queryAPI(apiClient(), apps, db); err != nil {
+			log.Printf("⚠️  OSV API query failed: %v", err)
+		}
+	}
+
+	vulnerable := 0
+	for i := range apps {
+		app := &apps[i]
+		app.Vulnerabilities = Match(app, db)
+		if len(app.Vulnerabilities) > 0 {
+			vulnerable++
+			log.Printf("  %s %s: %d known vulnerabilities", app.ID, app.Vulnerabilities[0].Version, len(app.Vulnerabilities))
+		}
+	}
+
+	log.Printf("✅ Found known vulnerabilities in %d apps", vulnerable)
+	return apps
+}
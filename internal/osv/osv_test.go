@@ -0,0 +1,268 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// goRecord affects the gh CLI's Go module before 2.40.1
+const goRecord = `{
+  "id": "GHSA-aaaa-bbbb-cccc",
+  "aliases": ["CVE-2024-0001"],
+  "summary": "Token leak in gh",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "github.com/cli/cli"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "2.0.0"}, {"fixed": "2.40.1"}]}]
+  }],
+  "database_specific": {"severity": "HIGH"}
+}`
+
+// gitRecord lists the affected tags of a repo fixed by a commit
+const gitRecord = `{
+  "id": "OSV-2024-1",
+  "summary": "Crash on malformed archive",
+  "affected": [{
+    "ranges": [{"type": "GIT", "repo": "https://gitlab.gnome.org/GNOME/file-roller.git", "events": [{"introduced": "0"}, {"fixed": "abc123"}]}],
+    "versions": ["43.0", "43.1", "44.0"]
+  }]
+}`
+
+// brewRecord uses a custom Homebrew ecosystem with a last_affected bound
+const brewRecord = `[{
+  "id": "LOCAL-1",
+  "summary": "Path traversal",
+  "affected": [{
+    "package": {"ecosystem": "Homebrew", "name": "bat"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"last_affected": "0.24.0"}]}]
+  }]
+}, {
+  "id": "LOCAL-2",
+  "summary": "Withdrawn report",
+  "withdrawn": "2024-05-01T00:00:00Z",
+  "affected": [{"package": {"ecosystem": "Homebrew", "name": "bat"}, "versions": ["0.24.0"]}]
+}]`
+
+func writeDump(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"GHSA-aaaa-bbbb-cccc.json": goRecord,
+		"nested/OSV-2024-1.json":   gitRecord,
+		"local.json":               brewRecord,
+		"README.md":                "not a record",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadDump(t *testing.T) {
+	db, err := LoadDump(writeDump(t))
+	if err != nil {
+		t.Fatalf("LoadDump: %v", err)
+	}
+	if db.Len() != 3 {
+		t.Errorf("loaded %d records, want 3 (withdrawn record skipped)", db.Len())
+	}
+
+	// The same records from a zip archive
+	archivePath := filepath.Join(t.TempDir(), "all.zip")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	w, _ := archive.Create("GHSA-aaaa-bbbb-cccc.json")
+	w.Write([]byte(goRecord))
+	archive.Close()
+	file.Close()
+
+	db, err = LoadDump(archivePath)
+	if err != nil {
+		t.Fatalf("LoadDump(zip): %v", err)
+	}
+	if db.Len() != 1 {
+		t.Errorf("loaded %d records from zip, want 1", db.Len())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	db, err := LoadDump(writeDump(t))
+	if err != nil {
+		t.Fatalf("LoadDump: %v", err)
+	}
+
+	t.Run("homebrew formula by Go module", func(t *testing.T) {
+		app := &models.App{
+			ID:           "gh",
+			PackageType:  "homebrew",
+			HomebrewInfo: &models.HomebrewInfo{Formula: "gh", Versions: []string{"2.40.0"}},
+			SourceRepo:   &models.SourceRepo{Type: "github", URL: "https://github.com/cli/cli", Owner: "cli", Repo: "cli"},
+			Releases: []models.Release{
+				{Version: "v2.41.0"},
+				{Version: "v2.40.2-rc.1", Prerelease: true},
+				{Version: "v2.40.1"},
+				{Version: "v2.40.0"},
+			},
+		}
+
+		got := Match(app, db)
+		want := []models.Vulnerability{{
+			ID:       "GHSA-aaaa-bbbb-cccc",
+			Aliases:  []string{"CVE-2024-0001"},
+			Summary:  "Token leak in gh",
+			URL:      "https://osv.dev/vulnerability/GHSA-aaaa-bbbb-cccc",
+			Severity: "high",
+			Version:  "2.40.0",
+			Fixed:    "2.40.1",
+			FixedBy:  "v2.40.1",
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Match =\n%+v\nwant\n%+v", got, want)
+		}
+
+		fix := app.Releases[2]
+		if !fix.Security || len(fix.Advisories) != 1 || fix.Advisories[0].CVEs[0].ID != "CVE-2024-0001" {
+			t.Errorf("fixing release not marked: %+v", fix)
+		}
+
+		// Matching again doesn't duplicate the advisory
+		Match(app, db)
+		if len(app.Releases[2].Advisories) != 1 {
+			t.Errorf("advisory attached %d times", len(app.Releases[2].Advisories))
+		}
+	})
+
+	t.Run("fixed version is not vulnerable", func(t *testing.T) {
+		app := &models.App{
+			PackageType:  "homebrew",
+			HomebrewInfo: &models.HomebrewInfo{Formula: "gh", Versions: []string{"2.40.1"}},
+			SourceRepo:   &models.SourceRepo{Type: "github", URL: "https://github.com/cli/cli", Owner: "cli", Repo: "cli"},
+		}
+		if got := Match(app, db); len(got) != 0 {
+			t.Errorf("Match = %+v, want none", got)
+		}
+	})
+
+	t.Run("flatpak by repo with affected tags", func(t *testing.T) {
+		app := &models.App{
+			ID:          "org.gnome.FileRoller",
+			PackageType: "flatpak",
+			Version:     "44.1",
+			SourceRepo:  &models.SourceRepo{Type: "gitlab", URL: "https://gitlab.gnome.org/GNOME/file-roller"},
+			Releases: []models.Release{
+				{Version: "44.1", Type: "gitlab-release"},
+				{Version: "44.0", Type: "gitlab-release", Sources: []models.ReleaseSource{{Type: "gitlab-release"}, {Type: "appstream"}}},
+				{Version: "43.1", Type: "appstream"},
+			},
+		}
+
+		got := Match(app, db)
+		if len(got) != 1 || got[0].Version != "44.0" || got[0].FixedBy != "44.1" || got[0].Fixed != "" {
+			t.Errorf("Match = %+v, want OSV-2024-1 on shipped 44.0 fixed by 44.1", got)
+		}
+	})
+
+	t.Run("custom ecosystem with last_affected", func(t *testing.T) {
+		app := &models.App{
+			PackageType:  "homebrew",
+			HomebrewInfo: &models.HomebrewInfo{Formula: "bat", Versions: []string{"0.24.0_1"}},
+			Releases:     []models.Release{{Version: "v0.25.0"}},
+		}
+
+		got := Match(app, db)
+		if len(got) != 1 || got[0].ID != "LOCAL-1" || got[0].FixedBy != "" {
+			t.Errorf("Match = %+v, want LOCAL-1 without a known fix", got)
+		}
+	})
+
+	t.Run("OS images are skipped", func(t *testing.T) {
+		app := &models.App{PackageType: "os", Version: "2.0.0", SourceRepo: &models.SourceRepo{Type: "github", URL: "https://github.com/cli/cli", Owner: "cli", Repo: "cli"}}
+		if got := Match(app, db); got != nil {
+			t.Errorf("Match = %+v, want nil", got)
+		}
+	})
+}
+
+func TestInRange(t *testing.T) {
+	events := []Event{{Fixed: "1.2.5"}, {Introduced: "0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}
+	tests := map[string]bool{
+		"1.0.0":     true,
+		"1.2.5":     false,
+		"1.9.0":     false,
+		"2.0.0-rc1": false,
+		"2.0.3":     true,
+		"2.1.0":     false,
+	}
+	for s, want := range tests {
+		v, _ := version.Parse(s)
+		if got := inRange(events, v); got != want {
+			t.Errorf("inRange(%s) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestQueryAPI(t *testing.T) {
+	var queries []apiQuery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/querybatch":
+			var body struct {
+				Queries []apiQuery `json:"queries"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			queries = body.Queries
+			w.Write([]byte(`{"results": [{"vulns": [{"id": "GHSA-aaaa-bbbb-cccc"}]}, {}]}`))
+		case "/vulns/GHSA-aaaa-bbbb-cccc":
+			w.Write([]byte(goRecord))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	original := APIURL
+	APIURL = server.URL
+	defer func() { APIURL = original }()
+
+	apps := []models.App{{
+		ID:          "io.github.cli.Gh",
+		PackageType: "flatpak",
+		Version:     "2.40.0",
+		SourceRepo:  &models.SourceRepo{Type: "github", URL: "https://github.com/cli/cli", Owner: "cli", Repo: "cli"},
+	}}
+
+	db := NewDatabase()
+	if err := queryAPI(server.Client(), apps, db); err != nil {
+		t.Fatalf("queryAPI: %v", err)
+	}
+
+	wantQueries := []apiQuery{
+		{Package: Package{Ecosystem: "GIT", Name: "https://github.com/cli/cli"}, Version: "2.40.0"},
+		{Package: Package{Ecosystem: "Go", Name: "github.com/cli/cli"}, Version: "v2.40.0"},
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("queries = %+v, want %+v", queries, wantQueries)
+	}
+	if db.Len() != 1 {
+		t.Fatalf("db has %d records, want 1", db.Len())
+	}
+	if got := Match(&apps[0], db); len(got) != 1 {
+		t.Errorf("Match after API query = %+v, want one vulnerability", got)
+	}
+}
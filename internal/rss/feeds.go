@@ -0,0 +1,98 @@
+package rss
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// ReleasesPerFeed is the number of feed releases kept per app
+const ReleasesPerFeed = 5
+
+// EnrichWithFeeds adds releases announced in each app's feed (App.Feed, declared in
+// source-overrides.json). Every feed release is prepended; versions other sources also report
+// are combined later by merge.Releases.
+func EnrichWithFeeds(apps []models.App) []models.App {
+	parser := NewParser(15 * time.Second)
+	ctx := context.Background()
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for i := range apps {
+		app := &apps[i]
+		if app.Feed == nil || app.Feed.URL == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *models.App) {
+			defer wg.Done()
+
+			releases, err := parser.FetchFeedReleases(ctx, *app.Feed)
+			if err != nil {
+				log.Printf("⚠️  Failed to fetch release feed for %s: %v", app.ID, err)
+				return
+			}
+
+			if len(releases) == 0 {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// Prepend feed releases; versions other sources also report are combined by the merge step
+			app.Releases = append(releases, app.Releases...)
+			log.Printf("✅ Added %d feed releases for %s", len(releases), app.ID)
+		}(app)
+	}
+
+	wg.Wait()
+	return apps
+}
+
+// FetchFeedReleases fetches a release feed and converts matching items to releases of
+// type "feed-release", newest first
+func (p *Parser) FetchFeedReleases(ctx context.Context, source models.FeedSource) ([]models.Release, error) {
+	var filter *regexp.Regexp
+	if source.Filter != "" {
+		re, err := regexp.Compile(source.Filter)
+		if err != nil {
+			return nil, err
+		}
+		filter = re
+	}
+
+	feed, err := p.FetchAndParse(ctx, source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Feeds covering many projects (KDE announcements, Discourse) are narrowed by title
+	if filter != nil {
+		items := feed.Items[:0]
+		for _, item := range feed.Items {
+			if filter.MatchString(item.Title) {
+				items = append(items, item)
+			}
+		}
+		feed.Items = items
+	}
+
+	releases := ConvertToReleases(feed, "feed-release")
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date.After(releases[j].Date)
+	})
+	if len(releases) > ReleasesPerFeed {
+		releases = releases[:ReleasesPerFeed]
+	}
+	return releases, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
 	"github.com/mmcdole/gofeed"
 )
 
@@ -40,17 +41,29 @@ func (p *Parser) FetchAndParse(ctx context.Context, url string) (*gofeed.Feed, e
 	return feed, nil
 }
 
-// ConvertToReleases converts RSS feed items to Release structs
+// ConvertToReleases converts RSS feed items to Release structs.
+// Items without a recognizable version (e.g., regular blog posts) are skipped.
 func ConvertToReleases(feed *gofeed.Feed, releaseType string) []models.Release {
 	releases := make([]models.Release, 0, len(feed.Items))
 
 	for _, item := range feed.Items {
+		itemVersion := ExtractVersion(item)
+		if itemVersion == "" {
+			continue
+		}
+
+		description := item.Description
+		if description == "" {
+			description = item.Content
+		}
+
 		release := models.Release{
-			Version:     extractVersion(item),
+			Version:     itemVersion,
 			Title:       item.Title,
-			Description: item.Description,
+			Description: description,
 			URL:         item.Link,
 			Type:        releaseType,
+			Prerelease:  version.IsPrerelease(itemVersion, version.Semver),
 		}
 
 		// Parse date (RSS uses Published, Atom uses Updated)
@@ -68,25 +81,16 @@ func ConvertToReleases(feed *gofeed.Feed, releaseType string) []models.Release {
 	return releases
 }
 
-// extractVersion tries to extract version from RSS item
-func extractVersion(item *gofeed.Item) string {
-	// Try to extract version from title (e.g., "v1.2.3 - Release Title")
-	if item.Title != "" {
-		// Look for version patterns in title
-		// This is a simple heuristic - may need refinement
-		for _, word := range []string{item.Title} {
-			if len(word) > 0 && (word[0] == 'v' || word[0] == 'V') {
-				return word
-			}
+// ExtractVersion finds the version an RSS item announces, looking at the title first, then
+// the link ("/releases/tag/v1.2.3") and GUID. Only dotted versions count, so years and post
+// IDs aren't mistaken for releases. Returns "" when none contains a version.
+func ExtractVersion(item *gofeed.Item) string {
+	for _, candidate := range []string{item.Title, item.Link, item.GUID} {
+		if v, ok := version.Parse(candidate); ok && len(v.Numbers) > 1 {
+			return v.Text()
 		}
 	}
-
-	// Fallback to using GUID or custom fields
-	if item.GUID != "" {
-		return item.GUID
-	}
-
-	return "unknown"
+	return ""
 }
 
 // FetchGitHubReleases fetches releases from a GitHub repository RSS feed
@@ -0,0 +1,74 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/mmcdole/gofeed"
+)
+
+func TestExtractVersion(t *testing.T) {
+	tests := []struct {
+		item gofeed.Item
+		want string
+	}{
+		{gofeed.Item{Title: "v1.2.3 - Spring cleaning"}, "1.2.3"},
+		{gofeed.Item{Title: "Fractal 9 released", Link: "https://gitlab.gnome.org/World/fractal/-/releases/9.0"}, "9.0"},
+		{gofeed.Item{Title: "KDE Gear 24.08.1"}, "24.08.1"},
+		{gofeed.Item{Title: "Firefox Beta 128.0b3 is out"}, "128.0b3"},
+		{gofeed.Item{Title: "Release candidate", GUID: "tag:github.com,2008:Repository/123/v6.2.0-rc1"}, "6.2.0-rc1"},
+		{gofeed.Item{Title: "Our plans for the year", GUID: "https://blog.example.org/?p=2024"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractVersion(&tt.item); got != tt.want {
+			t.Errorf("ExtractVersion(%q) = %q, want %q", tt.item.Title, got, tt.want)
+		}
+	}
+}
+
+func TestEnrichWithFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>KDE Announcements</title>
+  <item><title>KDE Gear 24.08.1</title><link>https://kde.org/announcements/gear/24.08.1/</link><description>Kate and friends</description><pubDate>Thu, 12 Sep 2024 00:00:00 +0000</pubDate></item>
+  <item><title>Plasma 6.1.5</title><link>https://kde.org/announcements/plasma/6/6.1.5/</link><pubDate>Tue, 10 Sep 2024 00:00:00 +0000</pubDate></item>
+  <item><title>KDE Gear 24.08.0</title><link>https://kde.org/announcements/gear/24.08.0/</link><pubDate>Thu, 22 Aug 2024 00:00:00 +0000</pubDate></item>
+  <item><title>Akademy 2024 recap</title><link>https://kde.org/akademy</link><pubDate>Mon, 16 Sep 2024 00:00:00 +0000</pubDate></item>
+</channel></rss>`)
+	}))
+	defer server.Close()
+
+	apps := []models.App{
+		{
+			ID:       "org.kde.kate",
+			Feed:     &models.FeedSource{URL: server.URL, Filter: `^KDE Gear`},
+			Releases: []models.Release{{Version: "24.08.0", Type: "appstream", Date: time.Date(2024, 8, 22, 0, 0, 0, 0, time.UTC)}},
+		},
+		{ID: "org.example.NoFeed"},
+	}
+
+	// Every matching item is added, including versions other sources know; the merge step combines them
+	releases := EnrichWithFeeds(apps)[0].Releases
+	if len(releases) != 3 {
+		t.Fatalf("Expected 2 feed releases plus the existing one, got %d: %+v", len(releases), releases)
+	}
+	if releases[0].Version != "24.08.1" || releases[0].Type != "feed-release" || releases[0].URL != "https://kde.org/announcements/gear/24.08.1/" {
+		t.Errorf("Unexpected feed release: %+v", releases[0])
+	}
+	if releases[1].Version != "24.08.0" || releases[1].Type != "feed-release" {
+		t.Errorf("Expected the feed release of an already known version to be kept, got %+v", releases[1])
+	}
+	if releases[2].Type != "appstream" {
+		t.Errorf("Existing release should be kept after feed releases, got %+v", releases[2])
+	}
+
+	if _, err := NewParser(time.Second).FetchFeedReleases(context.Background(), models.FeedSource{URL: server.URL, Filter: "("}); err == nil {
+		t.Error("Expected error for invalid filter")
+	}
+}
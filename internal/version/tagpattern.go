@@ -0,0 +1,24 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultTagPattern matches version-like tags such as "v1.2.3", "47.0", "bat-0.24.0" or "gnome-firmware-46.0"
+const DefaultTagPattern = `^(?:[A-Za-z][\w-]*[-_])?v?\d+(?:\.\d+)+`
+
+// TagPattern filters the tags the GitHub, GitLab, Gitea and git tag fetchers turn into releases;
+// tags that don't match are ignored. Override with SetTagPattern (the pipeline exposes it as
+// the -tag-pattern flag).
+var TagPattern = regexp.MustCompile(DefaultTagPattern)
+
+// SetTagPattern replaces TagPattern with the given regular expression
+func SetTagPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("compile tag pattern: %w", err)
+	}
+	TagPattern = re
+	return nil
+}
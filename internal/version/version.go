@@ -0,0 +1,290 @@
+// Package version parses and orders the version schemes seen across release sources:
+// semantic versions ("v1.2.3-rc.1"), calendar versions (Bluefin's "stable-20260203",
+// "24.08.1"), GNOME versions ("49.alpha", "49.rc", and the older even/odd minor scheme
+// "3.37.1") and Mozilla versions ("128.0b3", "128.0a1", "115.12.0esr").
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scheme identifies how a version is numbered
+type Scheme int
+
+const (
+	// Semver is the default dotted-number scheme ("1.2.3", "v2.0.0-beta.1")
+	Semver Scheme = iota
+	// CalVer versions start with a year ("2024.01", "20260203", "stable-20260203")
+	CalVer
+	// GNOME versions use alpha/beta/rc words after the major version ("49.beta"), and before
+	// GNOME 40 odd minor versions were development releases ("3.37.1")
+	GNOME
+	// Mozilla versions use a/b suffixes for nightly/beta builds and esr for extended support ("128.0b3", "115.12.0esr")
+	Mozilla
+)
+
+// String returns the scheme name
+func (s Scheme) String() string {
+	switch s {
+	case CalVer:
+		return "calver"
+	case GNOME:
+		return "gnome"
+	case Mozilla:
+		return "mozilla"
+	}
+	return "semver"
+}
+
+// Pre-release stages, lowest first. A release without a stage sorts above all of them.
+const (
+	stageNone = iota
+	stageDev
+	stageAlpha
+	stageBeta
+	stagePreview
+	stageRC
+)
+
+// stageNames maps pre-release words to their stage
+var stageNames = map[string]int{
+	"dev":      stageDev,
+	"nightly":  stageDev,
+	"snapshot": stageDev,
+	"alpha":    stageAlpha,
+	"a":        stageAlpha,
+	"beta":     stageBeta,
+	"b":        stageBeta,
+	"pre":      stagePreview,
+	"preview":  stagePreview,
+	"rc":       stageRC,
+}
+
+// stageKeys is the canonical word for each stage, used by Normalized
+var stageKeys = map[int]string{
+	stageDev:     "dev",
+	stageAlpha:   "alpha",
+	stageBeta:    "beta",
+	stagePreview: "pre",
+	stageRC:      "rc",
+}
+
+// numbersRe finds the runs of dotted numbers in a version
+var numbersRe = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// findNumbers returns the bounds of the version number in s. Digits inside a project name
+// ("libp2p-0.53.0", "k9s-v0.32.5", "x264-0.164") aren't the version, so numbers that start the
+// string or follow a separator ("-", "_", "/", "@", " ", ":", "[", "(" or such a "v") win,
+// dotted ones first.
+func findNumbers(s string) (start, end int, ok bool) {
+	runs := numbersRe.FindAllStringIndex(s, -1)
+	if len(runs) == 0 {
+		return 0, 0, false
+	}
+
+	separated := func(i int) bool {
+		if i > 0 && (s[i-1] == 'v' || s[i-1] == 'V') {
+			i--
+		}
+		return i == 0 || strings.ContainsRune("-_/@ :[(", rune(s[i-1]))
+	}
+	dotted := func(run []int) bool {
+		return strings.Contains(s[run[0]:run[1]], ".")
+	}
+
+	for _, prefer := range []func(run []int) bool{
+		func(run []int) bool { return separated(run[0]) && dotted(run) },
+		func(run []int) bool { return separated(run[0]) },
+		dotted,
+	} {
+		for _, run := range runs {
+			if prefer(run) {
+				return run[0], run[1], true
+			}
+		}
+	}
+	return runs[0][0], runs[0][1], true
+}
+
+// suffixRe reads a pre-release or ESR suffix after the numbers ("-rc.1", ".alpha", "b3", "esr").
+// Single-letter a/b stages are only recognized directly after a digit (Mozilla style), and a
+// suffix followed by more letters is a word, not a stage ("1.0-predictable").
+var suffixRe = regexp.MustCompile(`^([-.~_]?)(dev|nightly|snapshot|alpha|beta|preview|pre|rc|esr|a|b)[.-]?(\d*)`)
+
+// Version is a parsed version string
+type Version struct {
+	Original string
+	Prefix   string // Text before the numbers ("v", "stable-", "app-")
+	Numbers  []int
+	Scheme   Scheme
+	ESR      bool // Mozilla extended support release
+
+	text     string // The version as written, without prefix or trailing text
+	stage    int
+	stageNum int
+}
+
+// Parse parses a version, detecting its scheme. Returns false when the string contains no number.
+func Parse(s string) (Version, bool) {
+	return ParseScheme(s, Semver)
+}
+
+// ParseScheme parses a version, using hint as the scheme when the string itself doesn't
+// identify one. Callers pass GNOME for GNOME core modules so odd minors count as unstable.
+func ParseScheme(s string, hint Scheme) (Version, bool) {
+	trimmed := strings.TrimSpace(s)
+	start, end, ok := findNumbers(trimmed)
+	if !ok {
+		return Version{}, false
+	}
+
+	v := Version{Original: s, Prefix: trimmed[:start], Scheme: hint}
+	for _, part := range strings.Split(trimmed[start:end], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, false
+		}
+		v.Numbers = append(v.Numbers, n)
+	}
+
+	v.text = trimmed[start:end]
+	rest := strings.ToLower(trimmed[end:])
+	if suffix := suffixRe.FindStringSubmatch(rest); suffix != nil && !startsWithLetter(rest[len(suffix[0]):]) {
+		separator, word, number := suffix[1], suffix[2], suffix[3]
+		switch {
+		case word == "esr":
+			v.ESR = true
+			v.Scheme = Mozilla
+			v.text = trimmed[start : end+len(suffix[0])]
+		case (word == "a" || word == "b") && separator != "":
+			// "1.0-a" style suffixes are too ambiguous to treat as pre-releases
+		default:
+			v.text = trimmed[start : end+len(suffix[0])]
+			v.stage = stageNames[word]
+			v.stageNum, _ = strconv.Atoi(number)
+			switch {
+			case word == "a" || word == "b":
+				v.Scheme = Mozilla
+			case len(v.Numbers) == 1 && separator == ".":
+				v.Scheme = GNOME
+			}
+		}
+	}
+
+	if v.Scheme == Semver && v.Numbers[0] >= 1000 {
+		v.Scheme = CalVer
+	}
+	return v, true
+}
+
+// Text returns the version as written, without the prefix or any trailing text
+// ("Release v1.2.3-rc.1 notes" -> "1.2.3-rc.1", "128.0b3" -> "128.0b3")
+func (v Version) Text() string {
+	return v.text
+}
+
+// Prerelease reports whether the version is a development, alpha, beta or release candidate
+// build. Under the GNOME scheme, odd minor versions before GNOME 40 are also development
+// releases (3.37.x led to 3.38.0; GTK and GLib still number this way).
+func (v Version) Prerelease() bool {
+	if v.stage != stageNone {
+		return true
+	}
+	return v.Scheme == GNOME && len(v.Numbers) >= 2 && v.Numbers[0] < 40 && v.Numbers[1]%2 == 1
+}
+
+// Compare orders two versions by number, then by pre-release stage (dev < alpha < beta <
+// preview < rc < release) and stage number. Missing number components count as 0, so
+// "49.rc" sorts below "49.0". Returns 1 if v > o, -1 if v < o, and 0 if equal.
+func (v Version) Compare(o Version) int {
+	for i := 0; i < len(v.Numbers) || i < len(o.Numbers); i++ {
+		var a, b int
+		if i < len(v.Numbers) {
+			a = v.Numbers[i]
+		}
+		if i < len(o.Numbers) {
+			b = o.Numbers[i]
+		}
+		if a != b {
+			return sign(a - b)
+		}
+	}
+
+	if v.stage != o.stage {
+		// No stage means a final release, which sorts above every pre-release stage
+		if v.stage == stageNone {
+			return 1
+		}
+		if o.stage == stageNone {
+			return -1
+		}
+		return sign(v.stage - o.stage)
+	}
+	return sign(v.stageNum - o.stageNum)
+}
+
+// Normalized returns a canonical form of the version for matching the same release across
+// sources: the prefix and trailing zero components are dropped and pre-release stages use one
+// spelling ("v1.2.0" and "app-1.2" -> "1.2", "2.0.0-RC.1" -> "2-rc1", "128.0b3" -> "128-beta3").
+func (v Version) Normalized() string {
+	numbers := v.Numbers
+	for len(numbers) > 1 && numbers[len(numbers)-1] == 0 {
+		numbers = numbers[:len(numbers)-1]
+	}
+
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = strconv.Itoa(n)
+	}
+	key := strings.Join(parts, ".")
+
+	if v.stage != stageNone {
+		key += "-" + stageKeys[v.stage]
+		if v.stageNum > 0 {
+			key += strconv.Itoa(v.stageNum)
+		}
+	}
+	if v.ESR {
+		key += "esr"
+	}
+	return key
+}
+
+// Compare parses and compares two version strings.
+// Strings without a version sort below those with one.
+func Compare(a, b string) int {
+	va, okA := Parse(a)
+	vb, okB := Parse(b)
+	switch {
+	case okA && okB:
+		return va.Compare(vb)
+	case okA:
+		return 1
+	case okB:
+		return -1
+	}
+	return 0
+}
+
+// IsPrerelease reports whether a version string is a pre-release under the given scheme
+func IsPrerelease(s string, scheme Scheme) bool {
+	v, ok := ParseScheme(s, scheme)
+	return ok && v.Prerelease()
+}
+
+// startsWithLetter reports whether s starts with a letter
+func startsWithLetter(s string) bool {
+	return s != "" && s[0] >= 'a' && s[0] <= 'z'
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
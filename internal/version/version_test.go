@@ -0,0 +1,175 @@
+package version
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input      string
+		numbers    []int
+		scheme     Scheme
+		prerelease bool
+		esr        bool
+	}{
+		{"v1.2.3", []int{1, 2, 3}, Semver, false, false},
+		{"app-2.0.0-rc.1", []int{2, 0, 0}, Semver, true, false},
+		{"6.2.0-beta1", []int{6, 2, 0}, Semver, true, false},
+		{"1.2.3-alpine", []int{1, 2, 3}, Semver, false, false},
+		{"stable-20260203", []int{20260203}, CalVer, false, false},
+		{"lts.20251223", []int{20251223}, CalVer, false, false},
+		{"24.08.1", []int{24, 8, 1}, Semver, false, false},
+		{"49.rc", []int{49}, GNOME, true, false},
+		{"48.alpha.1", []int{48}, GNOME, true, false},
+		{"128.0b3", []int{128, 0}, Mozilla, true, false},
+		{"129.0a1", []int{129, 0}, Mozilla, true, false},
+		{"115.12.0esr", []int{115, 12, 0}, Mozilla, false, true},
+		{"libp2p-0.53.0", []int{0, 53, 0}, Semver, false, false},
+		{"python3-3.12.1", []int{3, 12, 1}, Semver, false, false},
+		{"k9s-v0.32.5", []int{0, 32, 5}, Semver, false, false},
+		{"x264-0.164", []int{0, 164}, Semver, false, false},
+		{"1.0rc1", []int{1, 0}, Semver, true, false},
+		{"1.0-predictable", []int{1, 0}, Semver, false, false},
+		{"1.0-preview2", []int{1, 0}, Semver, true, false},
+		{"1:2.36-1", []int{2, 36}, Semver, false, false},
+		{"prettier-3.0.0", []int{3, 0, 0}, Semver, false, false},
+		{"0.24.0-dev", []int{0, 24, 0}, Semver, true, false},
+	}
+
+	for _, tt := range tests {
+		v, ok := Parse(tt.input)
+		if !ok {
+			t.Errorf("Parse(%q) failed", tt.input)
+			continue
+		}
+		if len(v.Numbers) != len(tt.numbers) {
+			t.Errorf("Parse(%q).Numbers = %v, want %v", tt.input, v.Numbers, tt.numbers)
+		} else {
+			for i := range tt.numbers {
+				if v.Numbers[i] != tt.numbers[i] {
+					t.Errorf("Parse(%q).Numbers = %v, want %v", tt.input, v.Numbers, tt.numbers)
+					break
+				}
+			}
+		}
+		if v.Scheme != tt.scheme {
+			t.Errorf("Parse(%q).Scheme = %s, want %s", tt.input, v.Scheme, tt.scheme)
+		}
+		if v.Prerelease() != tt.prerelease {
+			t.Errorf("Parse(%q).Prerelease() = %v, want %v", tt.input, v.Prerelease(), tt.prerelease)
+		}
+		if v.ESR != tt.esr {
+			t.Errorf("Parse(%q).ESR = %v, want %v", tt.input, v.ESR, tt.esr)
+		}
+	}
+
+	if _, ok := Parse("nightly"); ok {
+		t.Error("Expected Parse to fail for a string without numbers")
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := map[string]string{
+		"v1.2.3":                    "1.2.3",
+		"Release v6.2.0-rc1 is out": "6.2.0-rc1",
+		"Firefox Beta 128.0b3":      "128.0b3",
+		"115.12.0esr":               "115.12.0esr",
+		"## [1.2.3] - ":             "1.2.3",
+		"1.0-predictable":           "1.0",
+		"gnome-firmware-46.0 (tag)": "46.0",
+	}
+	for input, want := range tests {
+		v, _ := Parse(input)
+		if got := v.Text(); got != want {
+			t.Errorf("Parse(%q).Text() = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestGNOMEEvenOdd(t *testing.T) {
+	tests := map[string]bool{
+		"3.37.1":  true,  // Development series before 3.38
+		"3.38.1":  false, // Stable series
+		"4.13.2":  true,  // GTK still uses odd minors for development
+		"47.1":    false, // GNOME 40+ minors are stable point releases
+		"47.beta": true,
+	}
+	for input, want := range tests {
+		if got := IsPrerelease(input, GNOME); got != want {
+			t.Errorf("IsPrerelease(%q, GNOME) = %v, want %v", input, got, want)
+		}
+	}
+
+	if IsPrerelease("3.37.1", Semver) {
+		t.Error("Odd minors should only be unstable under the GNOME scheme")
+	}
+}
+
+func TestCompareOrdering(t *testing.T) {
+	versions := []string{
+		"49.0", "48.2", "49.rc", "49.alpha", "49.beta", "49.1", "48.alpha.1",
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) > 0
+	})
+
+	want := []string{"49.1", "49.0", "49.rc", "49.beta", "49.alpha", "48.2", "48.alpha.1"}
+	for i := range want {
+		if versions[i] != want[i] {
+			t.Fatalf("Sorted versions = %v, want %v", versions, want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.10.0", "v1.9.0", 1},
+		{"1.2", "1.2.0", 0},
+		{"2.0.0-rc.2", "2.0.0-rc.10", -1},
+		{"2.0.0-rc.1", "2.0.0-beta.3", 1},
+		{"128.0b3", "128.0", -1},
+		{"128.0a1", "128.0b1", -1},
+		{"115.12.0esr", "115.11.0esr", 1},
+		{"stable-20260203", "stable-20251223", 1},
+		{"20260203", "1.2.3", 1},
+		{"latest", "0.1", -1},
+		{"libp2p-0.53.0", "libp2p-0.54.0", -1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestNormalized(t *testing.T) {
+	tests := map[string]string{
+		"v1.2.0":        "1.2",
+		"app-1.2":       "1.2",
+		"49.0":          "49",
+		"49.rc":         "49-rc",
+		"2.0.0-RC.1":    "2-rc1",
+		"128.0b3":       "128-beta3",
+		"115.12.0esr":   "115.12esr",
+		"gts-20260203":  "20260203",
+		"libp2p-0.53.0": "0.53",
+	}
+	for input, want := range tests {
+		v, ok := Parse(input)
+		if !ok {
+			t.Errorf("Parse(%q) failed", input)
+			continue
+		}
+		if got := v.Normalized(); got != want {
+			t.Errorf("Parse(%q).Normalized() = %q, want %q", input, got, want)
+		}
+	}
+}
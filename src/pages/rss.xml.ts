@@ -7,8 +7,12 @@ interface Release {
   date: string;
   title: string;
   description?: string;
+  excerpt?: string;
   url?: string;
   type: string;
+  prerelease?: boolean;
+  security?: boolean;
+  tags?: string[];
 }
 
 interface App {
@@ -48,18 +52,17 @@ export async function GET(context: APIContext) {
     description: 'Latest release updates from Bluefin OS, Flatpak applications, and Homebrew packages',
     site: context.site || 'https://castrojo.github.io/bluefin-releases/',
     items: recentReleases.map(({ app, release, parsedDate }) => {
-      const description = release.description 
-        ? stripHtmlTags(release.description).substring(0, 500) 
-        : app.summary;
+      const description = release.excerpt || app.summary;
       
       return {
-        title: `${app.name} ${release.version}`,
+        title: `${app.name} ${release.version}${release.prerelease ? ' (pre-release)' : ''}${release.security ? ' (security)' : ''}`,
         pubDate: parsedDate,
         description: description,
         link: app.flathubUrl,
         categories: [
           app.packageType || 'unknown',
-          ...(app.isVerified ? ['verified'] : [])
+          ...(app.isVerified ? ['verified'] : []),
+          ...(release.tags || [])
         ],
         customData: `
           <app:icon>${app.icon || ''}</app:icon>
@@ -74,11 +77,3 @@ export async function GET(context: APIContext) {
     },
   });
 }
-
-// Helper function to strip HTML tags for plain text descriptions
-function stripHtmlTags(html: string): string {
-  return html
-    .replace(/<[^>]*>/g, '')
-    .replace(/\s+/g, ' ')
-    .trim();
-}
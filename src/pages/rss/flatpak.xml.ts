@@ -7,8 +7,12 @@ interface Release {
   date: string;
   title: string;
   description?: string;
+  excerpt?: string;
   url?: string;
   type: string;
+  prerelease?: boolean;
+  security?: boolean;
+  tags?: string[];
 }
 
 interface App {
@@ -50,28 +54,20 @@ export async function GET(context: APIContext) {
     description: 'Latest Flatpak application release updates',
     site: context.site || 'https://castrojo.github.io/bluefin-releases/',
     items: recentReleases.map(({ app, release, parsedDate }) => {
-      const description = release.description 
-        ? stripHtmlTags(release.description).substring(0, 500) 
-        : app.summary;
+      const description = release.excerpt || app.summary;
       
       return {
-        title: `${app.name} ${release.version}`,
+        title: `${app.name} ${release.version}${release.prerelease ? ' (pre-release)' : ''}${release.security ? ' (security)' : ''}`,
         pubDate: parsedDate,
         description: description,
         link: app.flathubUrl,
         categories: [
           'flatpak',
-          ...(app.isVerified ? ['verified'] : [])
+          ...(app.isVerified ? ['verified'] : []),
+          ...(release.tags || [])
         ],
       };
     }),
     customData: `<language>en-us</language>`,
   });
 }
-
-function stripHtmlTags(html: string): string {
-  return html
-    .replace(/<[^>]*>/g, '')
-    .replace(/\s+/g, ' ')
-    .trim();
-}
@@ -10,6 +10,7 @@ export interface FilterState {
   packageType: string;
   category: string;
   appSet: string;
+  brewfile: string;
   days: string;
 }
 
@@ -64,6 +65,15 @@ export const matchesAppSet = (
   return (card.dataset.appSet || '') === appSet;
 };
 
+// Pure function: Check if card matches Brewfile filter
+export const matchesBrewfile = (
+  card: HTMLElement,
+  brewfile: string
+): boolean => {
+  if (!brewfile) return true;
+  return (card.dataset.brewfile || '') === brewfile;
+};
+
 // Pure function: Check if card matches date filter
 export const matchesDateRange = (
   card: HTMLElement,
@@ -92,6 +102,7 @@ export const cardMatchesFilters = (
     matchesPackageType(card, state.packageType) &&
     matchesCategory(card, state.category) &&
     matchesAppSet(card, state.appSet) &&
+    matchesBrewfile(card, state.brewfile) &&
     matchesDateRange(card, state.days, now)
   );
 };
@@ -141,6 +152,7 @@ export const getActiveFilters = (
   if (state.packageType) filters.push(`Type: ${getSelectText('filter-package-type')}`);
   if (state.category) filters.push(`Category: ${getSelectText('filter-category')}`);
   if (state.appSet) filters.push(`App Set: ${getSelectText('filter-app-set')}`);
+  if (state.brewfile) filters.push(`Brewfile: ${getSelectText('filter-brewfile')}`);
   if (state.days) filters.push(`Updated: ${getSelectText('filter-date')}`);
   
   return filters;
@@ -243,6 +255,7 @@ export const initFilters = (container: HTMLElement): void => {
   const packageTypeSelect = document.getElementById('filter-package-type') as HTMLSelectElement;
   const categorySelect = document.getElementById('filter-category') as HTMLSelectElement;
   const appSetSelect = document.getElementById('filter-app-set') as HTMLSelectElement;
+  const brewfileSelect = document.getElementById('filter-brewfile') as HTMLSelectElement;
   const dateSelect = document.getElementById('filter-date') as HTMLSelectElement;
   const clearAllBtn = document.getElementById('clear-all-filters') as HTMLButtonElement;
   
@@ -269,6 +282,7 @@ export const initFilters = (container: HTMLElement): void => {
     packageType: packageTypeSelect.value,
     category: categorySelect.value,
     appSet: appSetSelect.value,
+    brewfile: brewfileSelect ? brewfileSelect.value : '',
     days: dateSelect.value,
   });
   
@@ -305,6 +319,7 @@ export const initFilters = (container: HTMLElement): void => {
     packageTypeSelect.value = '';
     categorySelect.value = '';
     appSetSelect.value = '';
+    if (brewfileSelect) brewfileSelect.value = '';
     dateSelect.value = '';
     handleApplyFilters();
   };
@@ -317,6 +332,9 @@ export const initFilters = (container: HTMLElement): void => {
   packageTypeSelect.addEventListener('change', handleApplyFilters);
   categorySelect.addEventListener('change', handleApplyFilters);
   appSetSelect.addEventListener('change', handleApplyFilters);
+  if (brewfileSelect) {
+    brewfileSelect.addEventListener('change', handleApplyFilters);
+  }
   dateSelect.addEventListener('change', handleApplyFilters);
   if (clearAllBtn) {
     clearAllBtn.addEventListener('click', handleClearAll);